@@ -1,41 +1,280 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync/atomic"
 	"syscall"
+	"time"
+	_ "time/tzdata"
 
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/api"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/application"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/config"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/discord"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/email"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/filestore"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/notion"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/slack"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/teams"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/webhook"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/scheduler"
 )
 
+const usage = `Usage: notion-notifier [flags] [command]
+
+Commands:
+  serve     run as a daemon: scheduled notifications and HTTP API (default)
+  run-once  send notifications once and exit; exits non-zero on failure
+            (with -job, run only the named job)
+  preview   print the messages that would be sent without sending them
+  register-commands
+            register the Discord slash commands (discord.bot.application_id and token)
+  snooze    stop notifications for -task <id> or -project <name> until -until
+            ("2006-01-02" or RFC3339) or for -days; without either, until unsnoozed
+  unsnooze  remove the snooze for -task <id> or -project <name>
+  snoozes   list active snoozes
+
+Flags:
+`
+
 func main() {
 	configPath := flag.String("config", "/etc/config/notion-notifier/config.yaml", "path to config file")
+	jobName := flag.String("job", "", "run-once: run only this job from the jobs config")
+	snoozeTask := flag.String("task", "", "snooze/unsnooze: task ID")
+	snoozeProject := flag.String("project", "", "snooze/unsnooze: project name")
+	snoozeUntil := flag.String("until", "", `snooze: stop notifications until this time ("2006-01-02" or RFC3339)`)
+	snoozeDays := flag.Int("days", 0, "snooze: stop notifications for this many days")
+	nowOverride := flag.String("now", "", `evaluate tasks as of this time ("2006-01-02" or RFC3339) instead of the current time`)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	// フラグはコマンドの前後どちらにも書ける
+	command := "serve"
+	if flag.NArg() > 0 {
+		command = flag.Arg(0)
+		if err := flag.CommandLine.Parse(flag.Args()[1:]); err != nil {
+			os.Exit(2)
+		}
+		if flag.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "unexpected arguments: %v\n", flag.Args())
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
 	cfg, err := config.Load(*configPath)
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	notionClient := notion.NewClient(cfg.Notion.APIToken, cfg.Notion.DatabaseID)
-	discordClient := discord.NewWebhookClient(cfg.Discord.WebhookURL)
-	notificationService := application.NewNotificationService(notionClient, discordClient, cfg.Notification.DaysBefore)
+	loc := cfg.Notification.Location()
+	clk := clock.System(loc)
+	if *nowOverride != "" {
+		now, err := clock.Parse(*nowOverride, loc)
+		if err != nil {
+			log.Fatalf("invalid -now: %v", err)
+		}
+		log.Printf("Evaluating tasks as of %s", now.Format(time.RFC3339))
+		clk = clock.Fixed(now)
+	}
+
+	notificationService := newNotificationService(cfg, loc, clk)
+	s := newScheduler(cfg, loc, notificationService)
+
+	switch command {
+	case "serve":
+		serve(cfg, s, notificationService, loc, clk)
+	case "run-once":
+		runOnce(s, *jobName)
+	case "preview":
+		preview(notificationService)
+	case "register-commands":
+		registerCommands(cfg.Discord.Bot)
+	case "snooze":
+		until, err := snoozeDeadline(*snoozeUntil, *snoozeDays, loc, clk)
+		if err != nil {
+			log.Fatalf("invalid snooze period: %v", err)
+		}
+		snooze(notificationService, snoozeTarget(*snoozeTask, *snoozeProject), until)
+	case "unsnooze":
+		unsnooze(notificationService, snoozeTarget(*snoozeTask, *snoozeProject))
+	case "snoozes":
+		listSnoozes(notificationService, loc)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func newNotificationService(cfg *config.Config, loc *time.Location, clk clock.Clock) *application.NotificationService {
+	retryPolicy := newRetryPolicy(cfg.Retry)
+	propertyMapping := notionPropertyMapping(cfg.Notion.Properties)
+	if err := propertyMapping.Validate(); err != nil {
+		log.Fatalf("invalid notion property mapping: %v", err)
+	}
+
+	notionClient := notion.NewClient(cfg.Notion.APIToken, cfg.Notion.DatabaseID,
+		notion.WithPageSize(cfg.Notion.PageSize),
+		notion.WithMaxPages(cfg.Notion.MaxPages),
+		notion.WithPropertyMapping(propertyMapping),
+		notion.WithStatusMapping(statusMapping(cfg.Notion.StatusGroups)),
+		notion.WithPriorityMapping(priorityMapping(cfg.Notion.Priorities)),
+		notion.WithRetryPolicy(retryPolicy),
+		notion.WithLocation(loc),
+		notion.WithClock(clk),
+	)
+	channels, err := buildChannels(cfg, retryPolicy)
+	if err != nil {
+		log.Fatalf("invalid channel config: %v", err)
+	}
+	serviceOpts := []application.Option{application.WithClock(clk), application.WithLocation(loc)}
+	if cfg.Notification.StateFile != "" {
+		stateStore, err := filestore.NewStateStore(cfg.Notification.StateFile)
+		if err != nil {
+			log.Fatalf("failed to open notification state: %v", err)
+		}
+		serviceOpts = append(serviceOpts, application.WithStateStore(stateStore, cfg.Notification.ResendCooldown))
+	}
+	if cfg.Notification.SnoozeFile != "" {
+		snoozeStore, err := filestore.NewSnoozeStore(cfg.Notification.SnoozeFile)
+		if err != nil {
+			log.Fatalf("failed to open snooze state: %v", err)
+		}
+		serviceOpts = append(serviceOpts, application.WithSnoozeStore(snoozeStore))
+	}
+	serviceOpts = append(serviceOpts, application.WithReadingPace(task.Pace{
+		Mode:        task.PaceMode(cfg.Notification.ReadingPace.Mode),
+		PagesPerDay: cfg.Notification.ReadingPace.PagesPerDay,
+	}))
+	serviceOpts = append(serviceOpts,
+		application.WithDigestDays(cfg.Notification.Digest.Days),
+		application.WithHoursBeforeDeadline(cfg.Notification.HoursBefore),
+		application.WithEscalation(task.EscalationLadder{
+			Days:           cfg.Notification.Escalation.Days,
+			HourlyOnDueDay: cfg.Notification.Escalation.HourlyOnDueDay,
+		}),
+		application.WithDueDayMention(cfg.Notification.Escalation.DueDayMention),
+	)
+	if len(cfg.Notification.DaysBeforeByPriority) > 0 {
+		leadTimes := make(map[task.Priority]int)
+		for name, days := range cfg.Notification.DaysBeforeByPriority {
+			p, err := task.ParsePriority(name)
+			if err != nil {
+				log.Fatalf("invalid notification.days_before_by_priority: %v", err)
+			}
+			leadTimes[p] = days
+		}
+		serviceOpts = append(serviceOpts, application.WithPriorityLeadTimes(leadTimes))
+	}
+	if cfg.Notification.Overdue.Enabled {
+		serviceOpts = append(serviceOpts, application.WithOverdueAlerts(cfg.Notification.Overdue.MaxDays))
+	}
+	if a := cfg.Actions; a.Enabled() {
+		serviceOpts = append(serviceOpts, application.WithActions(application.ActionConfig{
+			BaseURL:      a.BaseURL,
+			Secret:       []byte(a.Secret),
+			DoneStatus:   task.Status(a.DoneStatus),
+			PagesStep:    a.PagesStep,
+			PostponeDays: a.PostponeDays,
+			TTL:          a.TTL,
+		}))
+	}
+	return application.NewNotificationService(notionClient, channels, cfg.Notification.DaysBefore, serviceOpts...)
+}
+
+// jobs が未指定の場合は check_schedule ですべての通知を送る "notify" ジョブだけを登録する。
+func newScheduler(cfg *config.Config, loc *time.Location, notificationService *application.NotificationService) *scheduler.Scheduler {
+	jobs := cfg.Jobs
+	if len(jobs) == 0 {
+		schedule := cfg.Notification.CheckSchedule
+		if schedule == "" {
+			schedule = "0 12 * * *"
+		}
+		jobs = []config.JobConfig{{Name: "notify", Schedule: schedule}}
+	}
+
+	s := scheduler.New(loc)
+	for _, j := range jobs {
+		if err := s.Register(scheduler.JobSpec{
+			Name:     j.Name,
+			Schedule: j.Schedule,
+			Timeout:  j.Timeout,
+			Enabled:  j.IsEnabled(),
+			Overlap:  scheduler.OverlapPolicy(j.Overlap),
+			Job:      alertJob(notificationService, j.Kinds),
+		}); err != nil {
+			log.Fatalf("invalid job config: %v", err)
+		}
+	}
+	return s
+}
+
+// kinds が空の場合は Run と同じく設定で有効なすべての通知を送る。
+func alertJob(notificationService *application.NotificationService, kinds []string) scheduler.Job {
+	ks := make([]notification.Kind, 0, len(kinds))
+	for _, k := range kinds {
+		ks = append(ks, notification.Kind(k))
+	}
+	return scheduler.JobFunc(func(ctx context.Context) (int, error) {
+		var sent atomic.Int64
+		ctx = application.WithSentCounter(ctx, &sent)
+		var err error
+		if len(ks) == 0 {
+			err = notificationService.Run(ctx)
+		} else {
+			err = notificationService.RunKinds(ctx, ks)
+		}
+		return int(sent.Load()), err
+	})
+}
 
-	schedule := cfg.Notification.CheckSchedule
-	if schedule == "" {
-		schedule = "0 12 * * *"
+func serve(cfg *config.Config, s *scheduler.Scheduler, notificationService *application.NotificationService, loc *time.Location, clk clock.Clock) {
+	port := cfg.Server.Port
+	if port == 0 {
+		port = 8080
+	}
+	serverOpts := []api.Option{api.WithClock(clk), api.WithLocation(loc), api.WithAdminToken(cfg.Server.AdminToken)}
+	var interactions *discord.InteractionHandler
+	if cfg.Discord.Bot.Enabled() {
+		var err error
+		interactions, err = discord.NewInteractionHandler(cfg.Discord.Bot.PublicKey, notificationService)
+		if err != nil {
+			log.Fatalf("invalid discord bot config: %v", err)
+		}
+		serverOpts = append(serverOpts, api.WithInteractions(interactions))
+	}
+	if cfg.Actions.Enabled() {
+		serverOpts = append(serverOpts, api.WithActions(notificationService))
 	}
+	if cfg.Notification.SnoozeFile != "" {
+		serverOpts = append(serverOpts, api.WithSnoozes(notificationService))
+	}
+	server := api.NewServer(port, s, notificationService, serverOpts...)
+	go func() {
+		if err := server.Start(); err != nil {
+			log.Fatalf("failed to start HTTP server: %v", err)
+		}
+	}()
 
-	s := scheduler.New(schedule, notificationService)
 	if err := s.Start(); err != nil {
 		log.Fatalf("failed to start scheduler: %v", err)
 	}
+	server.SetReady(true)
 
 	if os.Getenv("RUN_ON_STARTUP") == "true" {
 		if err := s.RunNow(); err != nil {
@@ -47,5 +286,264 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("failed to shutdown HTTP server: %v", err)
+	}
+	// 受け付け済みのスラッシュコマンドの結果を送り終えてから終了する
+	if interactions != nil {
+		if err := interactions.Shutdown(ctx); err != nil {
+			log.Printf("failed to finish discord commands: %v", err)
+		}
+	}
 	s.Stop()
 }
+
+// k8s の CronJob などから 1 回だけ実行する。失敗した場合は終了コード 1 で終わる。
+// job を指定しない場合は有効なジョブをすべて実行する。
+func runOnce(s *scheduler.Scheduler, job string) {
+	var err error
+	if job != "" {
+		err = s.RunJob(job)
+	} else {
+		err = s.RunNow()
+	}
+	if err != nil {
+		log.Fatalf("run failed: %v", err)
+	}
+	log.Println("Run completed successfully")
+}
+
+// 送信されるはずのメッセージを標準出力に書き出す。Discord には送信しない。
+func preview(notificationService *application.NotificationService) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	previews, err := notificationService.Preview(ctx)
+	for _, p := range previews {
+		fmt.Printf("===== [%s] %s =====\n%s\n", p.Channel, p.Kind, p.Message.Text())
+	}
+	if len(previews) == 0 && err == nil {
+		fmt.Println("No notifications would be sent.")
+	}
+	if err != nil {
+		log.Fatalf("preview failed: %v", err)
+	}
+}
+
+// スラッシュコマンドを登録する。guild_id を指定しない場合は反映に時間がかかることがある。
+func registerCommands(c config.DiscordBotConfig) {
+	if c.ApplicationID == "" || c.Token == "" {
+		log.Fatal("discord.bot.application_id and discord.bot.token are required to register commands")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := discord.NewBotClient(c.ApplicationID, c.Token).RegisterCommands(ctx, c.GuildID); err != nil {
+		log.Fatalf("failed to register commands: %v", err)
+	}
+	log.Println("Registered Discord slash commands")
+}
+
+// snoozeTarget は -task と -project のどちらか一方から対象を決める。Until は呼び出し側で設定する。
+func snoozeTarget(taskID, project string) notification.Snooze {
+	switch {
+	case taskID != "" && project != "":
+		log.Fatal("specify either -task or -project")
+	case taskID != "":
+		return notification.Snooze{Scope: notification.SnoozeTask, Key: taskID}
+	case project != "":
+		return notification.Snooze{Scope: notification.SnoozeProject, Key: project}
+	}
+	log.Fatal("-task or -project is required")
+	return notification.Snooze{}
+}
+
+// snoozeDeadline は -until または -days から期限を返す。どちらも指定しない場合はゼロ値（解除するまで）。
+func snoozeDeadline(until string, days int, loc *time.Location, clk clock.Clock) (time.Time, error) {
+	switch {
+	case until != "" && days != 0:
+		return time.Time{}, fmt.Errorf("specify either -until or -days")
+	case until != "":
+		return clock.Parse(until, loc)
+	case days < 0:
+		return time.Time{}, fmt.Errorf("-days must not be negative")
+	case days > 0:
+		return clk.Now().AddDate(0, 0, days), nil
+	}
+	return time.Time{}, nil
+}
+
+func snooze(notificationService *application.NotificationService, sn notification.Snooze, until time.Time) {
+	sn.Until = until
+	sn, err := notificationService.AddSnooze(context.Background(), sn)
+	if err != nil {
+		log.Fatalf("failed to snooze: %v", err)
+	}
+	log.Printf("Snoozed %s %q %s", sn.Scope, sn.Key, snoozePeriod(sn, until.Location()))
+}
+
+func unsnooze(notificationService *application.NotificationService, sn notification.Snooze) {
+	ok, err := notificationService.RemoveSnooze(context.Background(), sn.Scope, sn.Key)
+	if err != nil {
+		log.Fatalf("failed to unsnooze: %v", err)
+	}
+	if !ok {
+		log.Fatalf("no snooze for %s %q", sn.Scope, sn.Key)
+	}
+	log.Printf("Unsnoozed %s %q", sn.Scope, sn.Key)
+}
+
+func listSnoozes(notificationService *application.NotificationService, loc *time.Location) {
+	snoozes, err := notificationService.ListSnoozes(context.Background())
+	if err != nil {
+		log.Fatalf("failed to list snoozes: %v", err)
+	}
+	if len(snoozes) == 0 {
+		fmt.Println("No active snoozes.")
+	}
+	for _, sn := range snoozes {
+		fmt.Printf("%s\t%s\t%s\n", sn.Scope, sn.Name(), snoozePeriod(sn, loc))
+	}
+}
+
+func snoozePeriod(sn notification.Snooze, loc *time.Location) string {
+	if sn.Until.IsZero() {
+		return "until unsnoozed"
+	}
+	return "until " + sn.Until.In(loc).Format("2006-01-02 15:04")
+}
+
+func notionPropertyMapping(c config.NotionPropertiesConfig) notion.PropertyMapping {
+	prop := func(p config.NotionPropertyConfig) notion.Property {
+		return notion.Property{Name: p.Name, Type: p.Type}
+	}
+	return notion.PropertyMapping{
+		TaskName:    prop(c.TaskName),
+		Due:         prop(c.Due),
+		Status:      prop(c.Status),
+		Project:     prop(c.Project),
+		TaskType:    prop(c.TaskType),
+		StartDate:   prop(c.StartDate),
+		TotalPages:  prop(c.TotalPages),
+		ReadPages:   prop(c.ReadPages),
+		PagesPerDay: prop(c.PagesPerDay),
+		Priority:    prop(c.Priority),
+		Mute:        prop(c.Mute),
+	}
+}
+
+func statusMapping(c config.StatusGroupsConfig) map[string]task.StatusGroup {
+	m := make(map[string]task.StatusGroup)
+	for group, names := range map[task.StatusGroup][]string{
+		task.StatusGroupToDo:       c.ToDo,
+		task.StatusGroupInProgress: c.InProgress,
+		task.StatusGroupComplete:   c.Complete,
+	} {
+		for _, name := range names {
+			m[name] = group
+		}
+	}
+	return m
+}
+
+// 空の場合は nil を返し、notion の既定の対応を使う。
+func priorityMapping(c config.PrioritiesConfig) map[string]task.Priority {
+	m := make(map[string]task.Priority)
+	for p, names := range map[task.Priority][]string{
+		task.PriorityHigh:   c.High,
+		task.PriorityMedium: c.Medium,
+		task.PriorityLow:    c.Low,
+	} {
+		for _, name := range names {
+			m[name] = p
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func newRetryPolicy(c config.RetryConfig) httpretry.Policy {
+	p := httpretry.DefaultPolicy()
+	if c.MaxAttempts > 0 {
+		p.MaxAttempts = c.MaxAttempts
+	}
+	if c.InitialBackoff > 0 {
+		p.InitialBackoff = c.InitialBackoff
+	}
+	if c.MaxBackoff > 0 {
+		p.MaxBackoff = c.MaxBackoff
+	}
+	return p
+}
+
+// channels が未指定の場合は discord.webhook_url をすべての通知を受け取る "default" チャネルとして扱う。
+func buildChannels(cfg *config.Config, retryPolicy httpretry.Policy) ([]notification.Channel, error) {
+	if len(cfg.Channels) == 0 {
+		return []notification.Channel{{
+			Name:     "default",
+			Notifier: discord.NewWebhookClient(cfg.Discord.WebhookURL, discord.WithRetryPolicy(retryPolicy)),
+		}}, nil
+	}
+
+	channels := make([]notification.Channel, 0, len(cfg.Channels))
+	for _, c := range cfg.Channels {
+		notifier, err := newNotifier(c, retryPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %w", c.Name, err)
+		}
+		ch := notification.Channel{
+			Name:     c.Name,
+			Notifier: notifier,
+		}
+		for _, r := range c.Routes {
+			rule := notification.Rule{
+				Projects:  r.Projects,
+				TaskTypes: r.TaskTypes,
+			}
+			for _, k := range r.Kinds {
+				rule.Kinds = append(rule.Kinds, notification.Kind(k))
+			}
+			for _, sev := range r.Severities {
+				rule.Severities = append(rule.Severities, notification.Severity(sev))
+			}
+			ch.Rules = append(ch.Rules, rule)
+		}
+		channels = append(channels, ch)
+	}
+	return channels, nil
+}
+
+func newEmailClient(c config.EmailConfig) (*email.SMTPClient, error) {
+	port := c.Port
+	if port == 0 {
+		port = 587
+	}
+	opts := []email.Option{email.WithTimeout(c.Timeout)}
+	if c.StartTLS {
+		opts = append(opts, email.WithStartTLS(nil))
+	}
+	if c.Username != "" {
+		opts = append(opts, email.WithAuth(c.Username, c.Password))
+	}
+	return email.NewSMTPClient(net.JoinHostPort(c.Host, strconv.Itoa(port)), c.From, c.To, opts...)
+}
+
+// newNotifier は type に応じた通知先を作る。type を省略した場合は Discord。
+func newNotifier(c config.ChannelConfig, retryPolicy httpretry.Policy) (notification.Notifier, error) {
+	switch c.Type {
+	case "slack":
+		return slack.NewWebhookClient(c.WebhookURL, slack.WithRetryPolicy(retryPolicy)), nil
+	case "teams":
+		return teams.NewWebhookClient(c.WebhookURL, teams.WithRetryPolicy(retryPolicy)), nil
+	case "webhook":
+		return webhook.NewClient(c.WebhookURL, c.Template, webhook.WithRetryPolicy(retryPolicy))
+	case "email":
+		return newEmailClient(c.Email)
+	default:
+		return discord.NewWebhookClient(c.WebhookURL, discord.WithRetryPolicy(retryPolicy)), nil
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/application"
+)
+
+// 操作リンクはチャットやメールのリンクプレビューで GET されることがあるため、
+// GET では確認画面を返すだけにして、ボタンを押した POST でタスクを更新する。
+// リバースプロキシでパスの前に prefix が付いていても（actions.base_url が https://host/notifier など）
+// 確認画面と同じ場所に POST されるよう、フォームの送信先は相対 URL にする。
+
+var actionPage = template.Must(template.New("action").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif;">
+<h2>{{.Title}}</h2>
+<p>{{.Message}}</p>
+{{if .Token}}<form method="post" action="actions">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">実行する</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+type actionPageData struct {
+	Title   string
+	Message string
+	// 空でない場合は実行ボタンを表示する
+	Token string
+}
+
+func (s *Server) handleActionConfirm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	a, err := s.actions.ParseAction(token)
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+	writeActionPage(w, http.StatusOK, actionPageData{Title: "タスクの更新", Message: a.Description(), Token: token})
+}
+
+func (s *Server) handleActionPerform(w http.ResponseWriter, r *http.Request) {
+	a, err := s.actions.PerformAction(r.Context(), r.PostFormValue("token"))
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+	writeActionPage(w, http.StatusOK, actionPageData{Title: "更新しました", Message: a.Description()})
+}
+
+func writeActionError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	message := "Notion の更新に失敗しました。時間をおいてもう一度試してください。"
+	switch {
+	case errors.Is(err, application.ErrActionsNotConfigured):
+		// 操作リンクを発行していないので、存在しないページとして扱う
+		status, message = http.StatusNotFound, "操作リンクは有効になっていません。"
+	case errors.Is(err, application.ErrInvalidActionToken):
+		status, message = http.StatusBadRequest, "リンクが正しくありません。"
+	case errors.Is(err, application.ErrActionExpired):
+		status, message = http.StatusGone, "リンクの有効期限が切れています。"
+	case errors.Is(err, application.ErrActionConflict):
+		status, message = http.StatusConflict, "リンクを作成した後にタスクが更新されています。最新の通知のリンクを使ってください。"
+	default:
+		log.Printf("action error: %v", err)
+	}
+	writeActionPage(w, status, actionPageData{Title: "更新できませんでした", Message: message})
+}
+
+func writeActionPage(w http.ResponseWriter, status int, data actionPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := actionPage.Execute(w, data); err != nil {
+		log.Printf("failed to render action page: %v", err)
+	}
+}
@@ -0,0 +1,355 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/application"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/scheduler"
+)
+
+// Runner は通知ジョブの即時実行と状態の参照を行う。scheduler.Scheduler が実装する。
+// RunJob は該当するジョブがない場合に scheduler.ErrJobNotFound を、
+// 実行中のため実行しなかった場合に scheduler.ErrJobRunning を返す。
+type Runner interface {
+	RunNow() error
+	RunJob(name string) error
+	Jobs() []scheduler.JobStatus
+	Runs() []scheduler.RunRecord
+}
+
+// UpcomingTaskLister は通知対象となる締切間近のタスクを返す。
+// application.NotificationService が実装する。
+type UpcomingTaskLister interface {
+	UpcomingTasks(ctx context.Context) ([]*task.Task, error)
+}
+
+// ActionPerformer は通知の操作リンクのトークンを検証し、タスクを更新する。
+// application.NotificationService が実装する。
+type ActionPerformer interface {
+	ParseAction(token string) (*application.TaskAction, error)
+	PerformAction(ctx context.Context, token string) (*application.TaskAction, error)
+}
+
+// SnoozeManager は通知を止めるルールを管理する。application.NotificationService が実装する。
+type SnoozeManager interface {
+	ListSnoozes(ctx context.Context) ([]notification.Snooze, error)
+	// AddSnooze は保存したルールを返す（キーは前後の空白が除かれる）。
+	AddSnooze(ctx context.Context, s notification.Snooze) (notification.Snooze, error)
+	RemoveSnooze(ctx context.Context, scope notification.SnoozeScope, key string) (bool, error)
+}
+
+type Server struct {
+	httpServer   *http.Server
+	runner       Runner
+	tasks        UpcomingTaskLister
+	interactions http.Handler
+	actions      ActionPerformer
+	snoozes      SnoozeManager
+	adminToken   string
+	clock        clock.Clock
+	location     *time.Location
+	ready        atomic.Bool
+}
+
+type Option func(*Server)
+
+// Discord の Interactions Endpoint（POST /discord/interactions）を有効にする。
+// h は署名の検証も行うこと（discord.InteractionHandler）。
+func WithInteractions(h http.Handler) Option {
+	return func(s *Server) {
+		s.interactions = h
+	}
+}
+
+// 通知の操作リンク（GET/POST /actions）を有効にする。
+// GET は確認画面を返すだけで、POST したときにタスクを更新する。
+func WithActions(p ActionPerformer) Option {
+	return func(s *Server) {
+		s.actions = p
+	}
+}
+
+// 締切までの日数などの計算に使う時計を設定する。省略時はシステム時計。
+func WithClock(c clock.Clock) Option {
+	return func(s *Server) {
+		s.clock = c
+	}
+}
+
+// 締切までの日数を数えるタイムゾーン（notification.timezone）を設定する。
+// 省略時は時計（WithClock）が返す時刻のタイムゾーンで数える。
+func WithLocation(loc *time.Location) Option {
+	return func(s *Server) {
+		s.location = loc
+	}
+}
+
+// 通知を止めるルールの API（GET/POST /snoozes, DELETE /snoozes/{scope}/{key}）を有効にする。
+// ルールを変更する POST と DELETE は WithAdminToken を設定した場合のみ有効になる。
+func WithSnoozes(m SnoozeManager) Option {
+	return func(s *Server) {
+		s.snoozes = m
+	}
+}
+
+// 設定を変更する API に必要な Bearer トークン（server.admin_token）を設定する。
+// 設定した場合は /run, /jobs, /tasks/upcoming, GET /snoozes にもトークンが必要になる。
+func WithAdminToken(token string) Option {
+	return func(s *Server) {
+		s.adminToken = token
+	}
+}
+
+func NewServer(port int, runner Runner, tasks UpcomingTaskLister, opts ...Option) *Server {
+	s := &Server{
+		runner: runner,
+		tasks:  tasks,
+		clock:  clock.System(nil),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.httpServer = &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// readyz が 200 を返すかどうかを切り替える。スケジューラ起動後に true にする。
+func (s *Server) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+func (s *Server) Start() error {
+	log.Printf("HTTP server listening on %s", s.httpServer.Addr)
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.SetReady(false)
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.Handle("POST /run", s.restricted(s.handleRun))
+	mux.Handle("GET /jobs", s.restricted(s.handleJobs))
+	mux.Handle("GET /tasks/upcoming", s.restricted(s.handleUpcomingTasks))
+	if s.interactions != nil {
+		mux.Handle("POST /discord/interactions", s.interactions)
+	}
+	if s.actions != nil {
+		mux.HandleFunc("GET /actions", s.handleActionConfirm)
+		mux.HandleFunc("POST /actions", s.handleActionPerform)
+	}
+	if s.snoozes != nil {
+		mux.Handle("GET /snoozes", s.restricted(s.handleListSnoozes))
+		if s.adminToken != "" {
+			mux.Handle("POST /snoozes", s.requireAdmin(s.handleAddSnooze))
+			mux.Handle("DELETE /snoozes/{scope}/{key}", s.requireAdmin(s.handleRemoveSnooze))
+		}
+	}
+	return mux
+}
+
+// restricted はジョブの実行やタスクを返す API に使う。server.admin_token を設定した場合は requireAdmin と同じく
+// トークンを要求する。設定していない場合は serve を外部に公開しない構成とみなし、そのまま受け付ける
+// （操作リンクや Discord ボットを有効にする場合は config で admin_token を必須にしている）。
+func (s *Server) restricted(next http.HandlerFunc) http.Handler {
+	if s.adminToken == "" {
+		return next
+	}
+	return s.requireAdmin(next)
+}
+
+// requireAdmin は Authorization: Bearer <server.admin_token> のリクエストだけを next に渡す。
+func (s *Server) requireAdmin(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+			return
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// ?job=<name> を指定するとそのジョブだけを実行する。省略時は有効なジョブをすべて実行する。
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	var err error
+	if job := r.URL.Query().Get("job"); job != "" {
+		err = s.runner.RunJob(job)
+	} else {
+		err = s.runner.RunNow()
+	}
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, scheduler.ErrJobRunning) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		log.Printf("manual run error: %v", err)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ジョブの状態と直近の実行履歴（新しい順）を返す。
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := make([]jobResponse, 0)
+	for _, j := range s.runner.Jobs() {
+		jobs = append(jobs, newJobResponse(j))
+	}
+	runs := make([]runResponse, 0)
+	for _, rec := range s.runner.Runs() {
+		runs = append(runs, newRunResponse(rec))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"jobs": jobs, "runs": runs})
+}
+
+func (s *Server) handleUpcomingTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.tasks.UpcomingTasks(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	resp := make([]taskResponse, 0, len(tasks))
+	now := s.clock.Now()
+	if s.location != nil {
+		now = now.In(s.location)
+	}
+	for _, t := range tasks {
+		resp = append(resp, newTaskResponse(t, now))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tasks": resp})
+}
+
+type taskResponse struct {
+	ID                string  `json:"id"`
+	Name              string  `json:"name"`
+	ProjectName       string  `json:"project_name"`
+	Status            string  `json:"status"`
+	StatusGroup       string  `json:"status_group"`
+	Priority          string  `json:"priority"`
+	DueDate           *string `json:"due_date,omitempty"`
+	DueHasTime        bool    `json:"due_has_time"` // false の場合 due_date の時刻は意味を持たない
+	DaysUntilDeadline int     `json:"days_until_deadline"`
+}
+
+type jobResponse struct {
+	Name     string  `json:"name"`
+	Schedule string  `json:"schedule"`
+	Enabled  bool    `json:"enabled"`
+	Overlap  string  `json:"overlap"`
+	Running  bool    `json:"running"`
+	NextRun  *string `json:"next_run,omitempty"`
+}
+
+func newJobResponse(j scheduler.JobStatus) jobResponse {
+	resp := jobResponse{
+		Name:     j.Name,
+		Schedule: j.Schedule,
+		Enabled:  j.Enabled,
+		Overlap:  string(j.Overlap),
+		Running:  j.Running,
+	}
+	if !j.Next.IsZero() {
+		next := j.Next.Format(time.RFC3339)
+		resp.NextRun = &next
+	}
+	return resp
+}
+
+type runResponse struct {
+	Job        string `json:"job"`
+	Trigger    string `json:"trigger"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+	DurationMs int64  `json:"duration_ms"`
+	Sent       int    `json:"sent"`
+	Skipped    bool   `json:"skipped"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newRunResponse(r scheduler.RunRecord) runResponse {
+	resp := runResponse{
+		Job:        r.Job,
+		Trigger:    string(r.Trigger),
+		StartedAt:  r.Start.Format(time.RFC3339),
+		FinishedAt: r.End.Format(time.RFC3339),
+		DurationMs: r.Duration.Milliseconds(),
+		Sent:       r.Sent,
+		Skipped:    r.Skipped,
+	}
+	if r.Err != nil {
+		resp.Error = r.Err.Error()
+	}
+	return resp
+}
+
+func newTaskResponse(t *task.Task, now time.Time) taskResponse {
+	resp := taskResponse{
+		ID:                t.ID,
+		Name:              t.Name,
+		ProjectName:       t.ProjectName,
+		Status:            string(t.Status),
+		StatusGroup:       string(t.Group()),
+		Priority:          t.Priority.String(),
+		DueHasTime:        t.DueHasTime,
+		DaysUntilDeadline: t.DaysUntilDeadline(now),
+	}
+	if t.DueDate != nil {
+		due := t.DueDate.Format(time.RFC3339)
+		resp.DueDate = &due
+	}
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
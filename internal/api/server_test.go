@@ -0,0 +1,509 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/application"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/scheduler"
+)
+
+type mockRunner struct {
+	called int
+	jobs   []string
+	err    error
+	status []scheduler.JobStatus
+	runs   []scheduler.RunRecord
+}
+
+func (m *mockRunner) RunNow() error {
+	m.called++
+	return m.err
+}
+
+func (m *mockRunner) Jobs() []scheduler.JobStatus {
+	return m.status
+}
+
+func (m *mockRunner) Runs() []scheduler.RunRecord {
+	return m.runs
+}
+
+func (m *mockRunner) RunJob(name string) error {
+	if name != "deadlines" {
+		return fmt.Errorf("%w: %s", scheduler.ErrJobNotFound, name)
+	}
+	m.jobs = append(m.jobs, name)
+	return m.err
+}
+
+type mockTaskLister struct {
+	tasks []*task.Task
+	err   error
+}
+
+func (m *mockTaskLister) UpcomingTasks(ctx context.Context) ([]*task.Task, error) {
+	return m.tasks, m.err
+}
+
+func TestServer_Healthz(t *testing.T) {
+	s := NewServer(0, &mockRunner{}, &mockTaskLister{})
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	s := NewServer(0, &mockRunner{}, &mockTaskLister{})
+	handler := s.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before ready, got %d", rec.Code)
+	}
+
+	s.SetReady(true)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after ready, got %d", rec.Code)
+	}
+}
+
+func TestServer_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		runErr     error
+		wantStatus int
+		wantCalled int
+	}{
+		{name: "success", method: http.MethodPost, wantStatus: http.StatusOK, wantCalled: 1},
+		{name: "job error", method: http.MethodPost, runErr: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCalled: 1},
+		{name: "GET is not allowed", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed, wantCalled: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockRunner{err: tt.runErr}
+			s := NewServer(0, runner, &mockTaskLister{})
+
+			rec := httptest.NewRecorder()
+			s.routes().ServeHTTP(rec, httptest.NewRequest(tt.method, "/run", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if runner.called != tt.wantCalled {
+				t.Errorf("expected RunNow to be called %d times, got %d", tt.wantCalled, runner.called)
+			}
+		})
+	}
+}
+
+func TestServer_RunJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		job        string
+		runErr     error
+		wantStatus int
+		wantJobs   int
+	}{
+		{name: "known job", job: "deadlines", wantStatus: http.StatusOK, wantJobs: 1},
+		{name: "job error", job: "deadlines", runErr: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantJobs: 1},
+		{name: "unknown job", job: "nope", wantStatus: http.StatusNotFound, wantJobs: 0},
+		{name: "already running", job: "deadlines", runErr: scheduler.ErrJobRunning, wantStatus: http.StatusConflict, wantJobs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockRunner{err: tt.runErr}
+			s := NewServer(0, runner, &mockTaskLister{})
+
+			rec := httptest.NewRecorder()
+			s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/run?job="+tt.job, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if len(runner.jobs) != tt.wantJobs {
+				t.Errorf("expected %d job runs, got %v", tt.wantJobs, runner.jobs)
+			}
+			if runner.called != 0 {
+				t.Errorf("expected RunNow not to be called, got %d", runner.called)
+			}
+		})
+	}
+}
+
+func TestServer_Jobs(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	runner := &mockRunner{
+		status: []scheduler.JobStatus{
+			{Name: "deadlines", Schedule: "0 9 * * *", Enabled: true, Overlap: scheduler.OverlapSkip, Next: start.AddDate(0, 0, 1)},
+		},
+		runs: []scheduler.RunRecord{
+			{Job: "deadlines", Trigger: scheduler.TriggerSchedule, Start: start, End: start.Add(1500 * time.Millisecond), Duration: 1500 * time.Millisecond, Sent: 2},
+			{Job: "deadlines", Trigger: scheduler.TriggerManual, Start: start, End: start, Skipped: true, Err: scheduler.ErrJobRunning},
+		},
+	}
+	s := NewServer(0, runner, &mockTaskLister{})
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Jobs []jobResponse `json:"jobs"`
+		Runs []runResponse `json:"runs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Jobs) != 1 || body.Jobs[0].NextRun == nil || *body.Jobs[0].NextRun != "2026-03-03T09:00:00Z" {
+		t.Errorf("unexpected jobs: %+v", body.Jobs)
+	}
+	if len(body.Runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(body.Runs))
+	}
+	if body.Runs[0].Sent != 2 || body.Runs[0].DurationMs != 1500 || body.Runs[0].Error != "" {
+		t.Errorf("unexpected run: %+v", body.Runs[0])
+	}
+	if !body.Runs[1].Skipped || body.Runs[1].Error == "" {
+		t.Errorf("unexpected skipped run: %+v", body.Runs[1])
+	}
+}
+
+func TestServer_UpcomingTasks(t *testing.T) {
+	due := time.Now().Add(24 * time.Hour)
+	lister := &mockTaskLister{
+		tasks: []*task.Task{
+			task.NewTask("1", "Task 1", "Work", &due, task.StatusInProgress),
+		},
+	}
+	s := NewServer(0, &mockRunner{}, lister)
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/upcoming", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Tasks []taskResponse `json:"tasks"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(body.Tasks))
+	}
+	if body.Tasks[0].Name != "Task 1" || body.Tasks[0].ProjectName != "Work" {
+		t.Errorf("unexpected task: %+v", body.Tasks[0])
+	}
+	if body.Tasks[0].DaysUntilDeadline != 1 {
+		t.Errorf("expected days_until_deadline 1, got %d", body.Tasks[0].DaysUntilDeadline)
+	}
+}
+
+func TestServer_UpcomingTasks_Error(t *testing.T) {
+	s := NewServer(0, &mockRunner{}, &mockTaskLister{err: errors.New("notion down")})
+
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/upcoming", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("expected 502, got %d", rec.Code)
+	}
+}
+
+func TestServer_Interactions(t *testing.T) {
+	called := false
+	interactions := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	NewServer(0, &mockRunner{}, &mockTaskLister{}).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/discord/interactions", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without interactions, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(0, &mockRunner{}, &mockTaskLister{}, WithInteractions(interactions)).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/discord/interactions", nil))
+	if rec.Code != http.StatusOK || !called {
+		t.Errorf("expected interactions handler to be called, got %d", rec.Code)
+	}
+}
+
+type mockActionPerformer struct {
+	performed []string
+	err       error
+}
+
+func (m *mockActionPerformer) ParseAction(token string) (*application.TaskAction, error) {
+	switch token {
+	case "valid":
+		return &application.TaskAction{Kind: application.ActionDone, TaskID: "1", TaskName: "資料作成", Status: "Done"}, nil
+	case "expired":
+		return nil, application.ErrActionExpired
+	default:
+		return nil, application.ErrInvalidActionToken
+	}
+}
+
+func (m *mockActionPerformer) PerformAction(ctx context.Context, token string) (*application.TaskAction, error) {
+	a, err := m.ParseAction(token)
+	if err != nil {
+		return nil, err
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.performed = append(m.performed, a.TaskID)
+	return a, nil
+}
+
+func TestServer_Actions(t *testing.T) {
+	performer := &mockActionPerformer{}
+	handler := NewServer(0, &mockRunner{}, &mockTaskLister{}, WithActions(performer)).routes()
+
+	// GET は確認画面を返すだけで更新しない
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/actions?token=valid", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "「資料作成」を Done にする") || !strings.Contains(body, `method="post" action="actions"`) {
+		t.Errorf("expected confirmation page, got: %s", body)
+	}
+	if len(performer.performed) != 0 {
+		t.Errorf("expected GET not to perform the action, got %v", performer.performed)
+	}
+
+	form := url.Values{"token": {"valid"}}
+	req := httptest.NewRequest(http.MethodPost, "/actions", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || len(performer.performed) != 1 {
+		t.Errorf("expected action to be performed, got %d %v", rec.Code, performer.performed)
+	}
+}
+
+func TestServer_Actions_Errors(t *testing.T) {
+	tests := []struct {
+		token string
+		err   error
+		want  int
+	}{
+		{token: "tampered", want: http.StatusBadRequest},
+		{token: "expired", want: http.StatusGone},
+		{token: "valid", err: application.ErrActionsNotConfigured, want: http.StatusNotFound},
+		{token: "valid", err: fmt.Errorf("failed to update task 1: %w", application.ErrActionConflict), want: http.StatusConflict},
+		{token: "valid", err: errors.New("notion API error"), want: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		handler := NewServer(0, &mockRunner{}, &mockTaskLister{}, WithActions(&mockActionPerformer{err: tt.err})).routes()
+		req := httptest.NewRequest(http.MethodPost, "/actions", strings.NewReader("token="+tt.token))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("token %q: expected %d, got %d", tt.token, tt.want, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(0, &mockRunner{}, &mockTaskLister{}).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/actions?token=valid", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without actions, got %d", rec.Code)
+	}
+}
+
+type mockSnoozeManager struct {
+	snoozes []notification.Snooze
+	now     time.Time
+}
+
+func (m *mockSnoozeManager) ListSnoozes(ctx context.Context) ([]notification.Snooze, error) {
+	return m.snoozes, nil
+}
+
+func (m *mockSnoozeManager) AddSnooze(ctx context.Context, s notification.Snooze) (notification.Snooze, error) {
+	if !s.Until.IsZero() && s.Until.Before(m.now) {
+		return notification.Snooze{}, fmt.Errorf("%w: until is in the past", application.ErrInvalidSnooze)
+	}
+	s.Key = strings.TrimSpace(s.Key)
+	m.snoozes = append(m.snoozes, s)
+	return s, nil
+}
+
+func (m *mockSnoozeManager) RemoveSnooze(ctx context.Context, scope notification.SnoozeScope, key string) (bool, error) {
+	for i, s := range m.snoozes {
+		if s.Scope == scope && s.Key == key {
+			m.snoozes = append(m.snoozes[:i], m.snoozes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+const testAdminToken = "0123456789abcdef"
+
+// adminRequest は server.admin_token を付けたリクエストを返す。
+func adminRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return req
+}
+
+func TestServer_Snoozes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	manager := &mockSnoozeManager{now: now}
+	handler := NewServer(0, &mockRunner{}, &mockTaskLister{},
+		WithSnoozes(manager), WithAdminToken(testAdminToken), WithClock(clock.Fixed(now))).routes()
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{body: `{"project": "家事", "until": "2099-08-20T00:00:00+09:00"}`, want: http.StatusCreated},
+		{body: `{"task_id": " task-1 ", "days": 3}`, want: http.StatusCreated},
+		{body: `{"task_id": "task-1", "project": "家事"}`, want: http.StatusBadRequest},
+		{body: `{"days": 3}`, want: http.StatusBadRequest},
+		{body: `{"project": "家事", "until": "2000-01-01T00:00:00Z"}`, want: http.StatusBadRequest},
+		{body: `{"project": "家事", "until": "tomorrow"}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, adminRequest(http.MethodPost, "/snoozes", strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("POST %s: expected %d, got %d (%s)", tt.body, tt.want, rec.Code, rec.Body.String())
+		}
+		// 返すキーは保存したもの（DELETE で指定できるもの）
+		if strings.Contains(tt.body, "task-1") && rec.Code == http.StatusCreated && !strings.Contains(rec.Body.String(), `"key":"task-1"`) {
+			t.Errorf("POST %s: expected the stored key in the response, got %s", tt.body, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodGet, "/snoozes", nil))
+	var resp struct {
+		Snoozes []snoozeResponse `json:"snoozes"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Snoozes) != 2 || resp.Snoozes[0].Scope != "project" || resp.Snoozes[0].Key != "家事" {
+		t.Errorf("unexpected snoozes: %+v", resp.Snoozes)
+	}
+	// days は時計の現在時刻から数える
+	if until := resp.Snoozes[1].Until; until == nil || *until != "2026-03-04T09:00:00Z" {
+		t.Errorf("expected task snooze until 2026-03-04T09:00:00Z, got %v", until)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodDelete, "/snoozes/project/"+url.PathEscape("家事"), nil))
+	if rec.Code != http.StatusNoContent || len(manager.snoozes) != 1 {
+		t.Errorf("expected snooze to be removed, got %d %+v", rec.Code, manager.snoozes)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodDelete, "/snoozes/project/"+url.PathEscape("家事"), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for missing snooze, got %d", rec.Code)
+	}
+}
+
+func TestServer_Snoozes_AdminToken(t *testing.T) {
+	body := `{"project": "家事"}`
+
+	manager := &mockSnoozeManager{}
+	handler := NewServer(0, &mockRunner{}, &mockTaskLister{}, WithSnoozes(manager), WithAdminToken(testAdminToken)).routes()
+	for _, authorization := range []string{"", "Bearer wrong-token", testAdminToken} {
+		req := httptest.NewRequest(http.MethodPost, "/snoozes", strings.NewReader(body))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: expected 401, got %d", authorization, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/snoozes/project/x", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("DELETE without token: expected 401, got %d", rec.Code)
+	}
+	if len(manager.snoozes) != 0 {
+		t.Errorf("expected no snooze to be added, got %+v", manager.snoozes)
+	}
+
+	// トークンを設定していない場合は変更する API を公開しない
+	handler = NewServer(0, &mockRunner{}, &mockTaskLister{}, WithSnoozes(manager)).routes()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/snoozes", strings.NewReader(body)))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST without admin token configured: expected 405, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/snoozes", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /snoozes: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestServer_AdminTokenRequired(t *testing.T) {
+	runner := &mockRunner{}
+	handler := NewServer(0, runner, &mockTaskLister{}, WithSnoozes(&mockSnoozeManager{}), WithAdminToken(testAdminToken)).routes()
+
+	for _, target := range []struct{ method, path string }{
+		{http.MethodPost, "/run"},
+		{http.MethodGet, "/jobs"},
+		{http.MethodGet, "/tasks/upcoming"},
+		{http.MethodGet, "/snoozes"},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(target.method, target.path, nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without token: expected 401, got %d", target.method, target.path, rec.Code)
+		}
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, adminRequest(target.method, target.path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s %s with token: expected 200, got %d", target.method, target.path, rec.Code)
+		}
+	}
+	if runner.called != 1 {
+		t.Errorf("expected only the authorized run, got %d", runner.called)
+	}
+
+	// ヘルスチェックはトークンなしで使える
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /healthz: expected 200, got %d", rec.Code)
+	}
+}
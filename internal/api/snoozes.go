@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/application"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+type snoozeRequest struct {
+	TaskID  string `json:"task_id"`
+	Project string `json:"project"`
+	Label   string `json:"label"`
+	// RFC3339。until と days のどちらも省略した場合は解除するまで止める
+	Until string `json:"until"`
+	Days  int    `json:"days"`
+}
+
+type snoozeResponse struct {
+	Scope string  `json:"scope"`
+	Key   string  `json:"key"`
+	Label string  `json:"label,omitempty"`
+	Until *string `json:"until,omitempty"`
+}
+
+func newSnoozeResponse(sn notification.Snooze) snoozeResponse {
+	resp := snoozeResponse{Scope: string(sn.Scope), Key: sn.Key, Label: sn.Label}
+	if !sn.Until.IsZero() {
+		until := sn.Until.Format(time.RFC3339)
+		resp.Until = &until
+	}
+	return resp
+}
+
+func (s *Server) handleListSnoozes(w http.ResponseWriter, r *http.Request) {
+	snoozes, err := s.snoozes.ListSnoozes(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := make([]snoozeResponse, 0, len(snoozes))
+	for _, sn := range snoozes {
+		resp = append(resp, newSnoozeResponse(sn))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"snoozes": resp})
+}
+
+// task_id か project のどちらか一方を指定する。同じ対象のルールがある場合は上書きする。
+func (s *Server) handleAddSnooze(w http.ResponseWriter, r *http.Request) {
+	var req snoozeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	sn, err := req.snooze(s.clock.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sn, err = s.snoozes.AddSnooze(r.Context(), sn)
+	if errors.Is(err, application.ErrInvalidSnooze) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newSnoozeResponse(sn))
+}
+
+func (s *Server) handleRemoveSnooze(w http.ResponseWriter, r *http.Request) {
+	ok, err := s.snoozes.RemoveSnooze(r.Context(), notification.SnoozeScope(r.PathValue("scope")), r.PathValue("key"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("snooze not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (req snoozeRequest) snooze(now time.Time) (notification.Snooze, error) {
+	var sn notification.Snooze
+	switch {
+	case req.TaskID != "" && req.Project != "":
+		return sn, errors.New("specify either task_id or project")
+	case req.TaskID != "":
+		sn = notification.Snooze{Scope: notification.SnoozeTask, Key: req.TaskID}
+	case req.Project != "":
+		sn = notification.Snooze{Scope: notification.SnoozeProject, Key: req.Project}
+	default:
+		return sn, errors.New("task_id or project is required")
+	}
+	sn.Label = req.Label
+
+	switch {
+	case req.Until != "" && req.Days != 0:
+		return sn, errors.New("specify either until or days")
+	case req.Until != "":
+		until, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			return sn, fmt.Errorf("invalid until: %w", err)
+		}
+		sn.Until = until
+	case req.Days < 0:
+		return sn, errors.New("days must not be negative")
+	case req.Days > 0:
+		sn.Until = now.AddDate(0, 0, req.Days)
+	}
+	return sn, nil
+}
@@ -0,0 +1,268 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+// 通知に付ける操作リンク（完了にする・ページ数を進める・締切を延期する）。
+// Webhook の通知にはボタンを置けないため、操作内容を署名付きトークンにしてリンクの URL に含める。
+// トークンには更新後の値（例: 読んだページ数 120）を入れるので、同じリンクを何度開いても結果は変わらない。
+// 古いリンクで進捗を戻したり締切を早めたりしないよう、更新の前に現在の値を確認する。
+
+const (
+	defaultPagesStep    = 20
+	defaultPostponeDays = 1
+	defaultActionTTL    = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrActionsNotConfigured は操作リンク（WithActions）が設定されていない場合に返す。
+	ErrActionsNotConfigured = errors.New("actions are not configured")
+	// ErrInvalidActionToken はトークンの形式または署名が正しくない場合に返す。
+	ErrInvalidActionToken = errors.New("invalid action token")
+	// ErrActionExpired はトークンの有効期限が切れている場合に返す。
+	ErrActionExpired = errors.New("action token expired")
+	// ErrActionConflict はリンクの作成後にタスクが更新されていて、操作すると値が戻ってしまう場合に返す。
+	ErrActionConflict = errors.New("task has been updated since the action was issued")
+)
+
+// ActionConfig は通知に付ける操作リンクの設定。
+type ActionConfig struct {
+	// リンク先のサーバーの URL（例: "https://notifier.example.com"）。リンクは {BaseURL}/actions?token=... になる
+	BaseURL string
+	// トークンの署名に使う鍵
+	Secret []byte
+	// 「完了」で設定するステータス。省略時は task.StatusDone
+	DoneStatus task.Status
+	// 「+N ページ」で進めるページ数。省略時は 20
+	PagesStep int
+	// 「N 日延期」で延ばす日数。省略時は 1
+	PostponeDays int
+	// リンクの有効期間。省略時は 7 日
+	TTL time.Duration
+}
+
+// 締切・読書・締切超過の通知に、タスクを直接更新するリンクを付ける。
+func WithActions(cfg ActionConfig) Option {
+	return func(s *NotificationService) {
+		if cfg.DoneStatus == "" {
+			cfg.DoneStatus = task.StatusDone
+		}
+		if cfg.PagesStep <= 0 {
+			cfg.PagesStep = defaultPagesStep
+		}
+		if cfg.PostponeDays <= 0 {
+			cfg.PostponeDays = defaultPostponeDays
+		}
+		if cfg.TTL <= 0 {
+			cfg.TTL = defaultActionTTL
+		}
+		cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+		s.actions = &cfg
+	}
+}
+
+// ActionKind は操作の種類。
+type ActionKind string
+
+const (
+	ActionDone     ActionKind = "done"
+	ActionAddPages ActionKind = "pages"
+	ActionPostpone ActionKind = "postpone"
+)
+
+// TaskAction はトークンに含まれる操作の内容。
+type TaskAction struct {
+	Kind     ActionKind `json:"k"`
+	TaskID   string     `json:"id"`
+	TaskName string     `json:"n"`
+	// ActionDone で設定するステータス
+	Status task.Status `json:"s,omitempty"`
+	// ActionAddPages で設定する読んだページ数
+	ReadPages int `json:"p,omitempty"`
+	// ActionPostpone で設定する締切
+	Due        *time.Time `json:"d,omitempty"`
+	DueHasTime bool       `json:"t,omitempty"`
+	// 有効期限（Unix 秒）
+	Expires int64 `json:"e"`
+}
+
+// Description は確認画面に表示する操作の説明を返す。例: "「資料作成」を Done にする"
+func (a *TaskAction) Description() string {
+	switch a.Kind {
+	case ActionDone:
+		return fmt.Sprintf("「%s」を %s にする", a.TaskName, a.Status)
+	case ActionAddPages:
+		return fmt.Sprintf("「%s」の読んだページ数を %d にする", a.TaskName, a.ReadPages)
+	case ActionPostpone:
+		due := a.Due.Format("1/2")
+		if a.DueHasTime {
+			due = a.Due.Format("1/2 15:04")
+		}
+		return fmt.Sprintf("「%s」の締切を %s に延期する", a.TaskName, due)
+	default:
+		return string(a.Kind)
+	}
+}
+
+// ParseAction はトークンの署名と有効期限を検証し、操作の内容を返す。
+func (s *NotificationService) ParseAction(token string) (*TaskAction, error) {
+	if s.actions == nil {
+		return nil, ErrActionsNotConfigured
+	}
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidActionToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.sign(payload)) {
+		return nil, ErrInvalidActionToken
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidActionToken
+	}
+	var a TaskAction
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, ErrInvalidActionToken
+	}
+	if s.now().Unix() > a.Expires {
+		return nil, ErrActionExpired
+	}
+	return &a, nil
+}
+
+// PerformAction はトークンを検証し、その内容でタスクを更新する。
+func (s *NotificationService) PerformAction(ctx context.Context, token string) (*TaskAction, error) {
+	a, err := s.ParseAction(token)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a.Kind {
+	case ActionDone:
+		err = s.taskRepo.UpdateStatus(ctx, a.TaskID, a.Status)
+	case ActionAddPages, ActionPostpone:
+		if a.Kind == ActionPostpone && a.Due == nil {
+			return nil, ErrInvalidActionToken
+		}
+		var current *task.Task
+		if current, err = s.taskRepo.FetchTask(ctx, a.TaskID); err != nil {
+			return nil, fmt.Errorf("failed to fetch task %s: %w", a.TaskID, err)
+		}
+		err = s.applyProgress(ctx, a, current)
+	default:
+		return nil, ErrInvalidActionToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task %s: %w", a.TaskID, err)
+	}
+	return a, nil
+}
+
+// applyProgress はページ数・締切を、現在の値 current より進む場合だけ更新する。
+// すでに同じ値の場合は何もせず、値が戻る場合は ErrActionConflict を返す。
+func (s *NotificationService) applyProgress(ctx context.Context, a *TaskAction, current *task.Task) error {
+	switch a.Kind {
+	case ActionAddPages:
+		switch {
+		case current.ReadPages == a.ReadPages:
+			return nil
+		case current.ReadPages > a.ReadPages:
+			return ErrActionConflict
+		}
+		return s.taskRepo.UpdateReadPages(ctx, a.TaskID, a.ReadPages)
+	default:
+		switch {
+		case current.DueDate == nil:
+			return ErrActionConflict
+		case current.DueDate.Equal(*a.Due):
+			return nil
+		case current.DueDate.After(*a.Due):
+			return ErrActionConflict
+		}
+		return s.taskRepo.PostponeDue(ctx, a.TaskID, *a.Due, a.DueHasTime)
+	}
+}
+
+// taskActions は t に付ける操作リンクを kinds の順に返す。WithActions が設定されていない場合は nil。
+// 実行できない操作（締切のないタスクの延期など）は含めない。
+func (s *NotificationService) taskActions(t *task.Task, kinds ...ActionKind) []notification.Action {
+	if s.actions == nil {
+		return nil
+	}
+
+	var links []notification.Action
+	for _, kind := range kinds {
+		a := TaskAction{
+			Kind:     kind,
+			TaskID:   t.ID,
+			TaskName: t.Name,
+			Expires:  s.now().Add(s.actions.TTL).Unix(),
+		}
+		var label string
+		switch kind {
+		case ActionDone:
+			a.Status = s.actions.DoneStatus
+			label = "✅ 完了"
+		case ActionAddPages:
+			if t.TotalPages > 0 && t.ReadPages >= t.TotalPages {
+				continue
+			}
+			a.ReadPages = t.ReadPages + s.actions.PagesStep
+			if t.TotalPages > 0 {
+				a.ReadPages = min(a.ReadPages, t.TotalPages)
+			}
+			label = fmt.Sprintf("📖 +%dページ", a.ReadPages-t.ReadPages)
+		case ActionPostpone:
+			due, ok := s.postponedDue(t)
+			if !ok {
+				continue
+			}
+			a.Due = &due
+			a.DueHasTime = t.DueHasTime
+			label = fmt.Sprintf("📅 %d日延期", s.actions.PostponeDays)
+		}
+		links = append(links, notification.Action{Label: label, URL: s.actionURL(a)})
+	}
+	return links
+}
+
+// postponedDue は延期後の締切を返す。締切を過ぎている場合は今日から数える。
+func (s *NotificationService) postponedDue(t *task.Task) (time.Time, bool) {
+	if t.DueDate == nil {
+		return time.Time{}, false
+	}
+	now := s.now()
+	loc := now.Location()
+	due := t.DueDate.In(loc)
+	if today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc); due.Before(today) {
+		due = time.Date(now.Year(), now.Month(), now.Day(), due.Hour(), due.Minute(), 0, 0, loc)
+	}
+	return due.AddDate(0, 0, s.actions.PostponeDays), true
+}
+
+func (s *NotificationService) actionURL(a TaskAction) string {
+	data, _ := json.Marshal(a)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	token := payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+	return s.actions.BaseURL + "/actions?token=" + url.QueryEscape(token)
+}
+
+func (s *NotificationService) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.actions.Secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
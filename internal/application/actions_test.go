@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+func actionToken(t *testing.T, a notification.Action) string {
+	t.Helper()
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		t.Fatalf("invalid action url %q: %v", a.URL, err)
+	}
+	if u.Path != "/actions" {
+		t.Errorf("expected /actions path, got %s", a.URL)
+	}
+	return u.Query().Get("token")
+}
+
+func TestNotificationService_Actions(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	deadline := task.NewTask("1", "資料作成", "Work", &due, task.StatusNotStarted)
+	deadline.DueHasTime = true
+
+	repo := &mockTaskRepo{tasks: []*task.Task{deadline}}
+	service := NewNotificationService(repo, nil, 3,
+		WithClock(clock.Fixed(now)),
+		WithActions(ActionConfig{BaseURL: "https://notifier.example.com/", Secret: []byte("0123456789abcdef"), DoneStatus: "完了"}))
+
+	msg := service.buildNotificationMessage([]*task.Task{deadline})
+	actions := msg.Sections[0].Items[0].Actions
+	if len(actions) != 2 || actions[0].Label != "✅ 完了" || actions[1].Label != "📅 1日延期" {
+		t.Fatalf("unexpected actions: %+v", actions)
+	}
+
+	done, err := service.ParseAction(actionToken(t, actions[0]))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := done.Description(); got != "「資料作成」を 完了 にする" {
+		t.Errorf("Description() = %q", got)
+	}
+
+	for _, a := range actions {
+		if _, err := service.PerformAction(context.Background(), actionToken(t, a)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := []string{"1 status=完了", "1 due=2026-03-03T18:00:00Z has_time=true"}
+	if !slices.Equal(repo.updates, want) {
+		t.Errorf("updates = %v, want %v", repo.updates, want)
+	}
+}
+
+func TestNotificationService_Actions_ReadingPages(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	book := task.NewTask("2", "Go言語", "読書", &due, task.StatusInProgress)
+	book.TotalPages = 200
+	book.ReadPages = 190
+
+	repo := &mockTaskRepo{tasks: []*task.Task{book}}
+	service := NewNotificationService(repo, nil, 3,
+		WithClock(clock.Fixed(now)),
+		WithActions(ActionConfig{BaseURL: "https://notifier.example.com", Secret: []byte("0123456789abcdef")}))
+
+	msg := service.buildReadingNotificationMessage([]*task.Task{book})
+	actions := msg.Sections[0].Items[0].Actions
+	if len(actions) != 2 || actions[0].Label != "📖 +10ページ" {
+		t.Fatalf("expected pages action capped at total pages, got %+v", actions)
+	}
+	if _, err := service.PerformAction(context.Background(), actionToken(t, actions[0])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(repo.updates, []string{"2 read_pages=200"}) {
+		t.Errorf("unexpected updates: %v", repo.updates)
+	}
+}
+
+func TestNotificationService_Actions_OverduePostponeFromToday(t *testing.T) {
+	now := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	overdue := task.NewTask("3", "請求書", "Work", &due, task.StatusNotStarted)
+
+	repo := &mockTaskRepo{tasks: []*task.Task{overdue}}
+	service := NewNotificationService(repo, nil, 3,
+		WithClock(clock.Fixed(now)),
+		WithActions(ActionConfig{BaseURL: "https://notifier.example.com", Secret: []byte("0123456789abcdef"), PostponeDays: 2}))
+
+	msg := service.buildOverdueNotificationMessage([]*task.Task{overdue})
+	postpone := msg.Sections[0].Items[0].Actions[1]
+	if _, err := service.PerformAction(context.Background(), actionToken(t, postpone)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(repo.updates, []string{"3 due=2026-03-07T00:00:00Z has_time=false"}) {
+		t.Errorf("unexpected updates: %v", repo.updates)
+	}
+}
+
+func TestNotificationService_Actions_StaleLink(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	book := task.NewTask("2", "Go言語", "読書", &due, task.StatusInProgress)
+	book.TotalPages = 200
+	book.ReadPages = 100
+
+	repo := &mockTaskRepo{tasks: []*task.Task{book}}
+	service := NewNotificationService(repo, nil, 3,
+		WithClock(clock.Fixed(now)),
+		WithActions(ActionConfig{BaseURL: "https://notifier.example.com", Secret: []byte("0123456789abcdef")}))
+
+	actions := service.taskActions(book, ActionAddPages, ActionPostpone)
+	pages, postpone := actionToken(t, actions[0]), actionToken(t, actions[1])
+
+	// リンクを作った後に Notion で先に進められている
+	book.ReadPages = 150
+	later := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	book.DueDate = &later
+	for _, token := range []string{pages, postpone} {
+		if _, err := service.PerformAction(context.Background(), token); !errors.Is(err, ErrActionConflict) {
+			t.Errorf("expected ErrActionConflict, got %v", err)
+		}
+	}
+
+	// すでに同じ値になっている場合は何もしない
+	book.ReadPages = 120
+	if _, err := service.PerformAction(context.Background(), pages); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.updates) != 0 {
+		t.Errorf("expected no updates, got %v", repo.updates)
+	}
+}
+
+func TestNotificationService_ParseAction_Errors(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tk := task.NewTask("1", "資料作成", "Work", &due, task.StatusNotStarted)
+	cfg := ActionConfig{BaseURL: "https://notifier.example.com", Secret: []byte("0123456789abcdef"), TTL: time.Hour}
+
+	service := NewNotificationService(&mockTaskRepo{}, nil, 3, WithClock(clock.Fixed(now)), WithActions(cfg))
+	token := actionToken(t, service.taskActions(tk, ActionDone)[0])
+
+	if _, err := service.ParseAction(token[:len(token)-2] + "xx"); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("expected ErrInvalidActionToken for tampered signature, got %v", err)
+	}
+	other := NewNotificationService(&mockTaskRepo{}, nil, 3, WithClock(clock.Fixed(now)),
+		WithActions(ActionConfig{BaseURL: cfg.BaseURL, Secret: []byte("another-secret-key")}))
+	if _, err := other.ParseAction(token); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("expected ErrInvalidActionToken for another secret, got %v", err)
+	}
+	later := NewNotificationService(&mockTaskRepo{}, nil, 3, WithClock(clock.Fixed(now.Add(2*time.Hour))), WithActions(cfg))
+	if _, err := later.ParseAction(token); !errors.Is(err, ErrActionExpired) {
+		t.Errorf("expected ErrActionExpired, got %v", err)
+	}
+	if _, err := NewNotificationService(&mockTaskRepo{}, nil, 3).ParseAction(token); !errors.Is(err, ErrActionsNotConfigured) {
+		t.Errorf("expected ErrActionsNotConfigured, got %v", err)
+	}
+}
+
+func TestNotificationService_Actions_Disabled(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 1)
+	tk := task.NewTask("1", "資料作成", "Work", &due, task.StatusNotStarted)
+	service := NewNotificationService(&mockTaskRepo{}, nil, 3)
+
+	msg := service.buildNotificationMessage([]*task.Task{tk})
+	if actions := msg.Sections[0].Items[0].Actions; actions != nil {
+		t.Errorf("expected no actions without WithActions, got %+v", actions)
+	}
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+// チャットのコマンド（Discord のスラッシュコマンドなど）に応答するための操作。
+// 通知と同じリポジトリ・時計・読書ペースでタスクを評価し、通知と同じ形式のメッセージを返す。
+
+const (
+	weekCommandDays    = 7
+	defaultSnoozeDays  = 1
+	maxSnoozeCandidate = 10
+)
+
+// ErrSnoozeNotConfigured は通知の停止先（WithSnoozeStore）が設定されていない場合に返す。
+var ErrSnoozeNotConfigured = errors.New("snooze store is not configured")
+
+// TasksToday は今日が締切のタスクと締切を過ぎたタスクを返す。
+func (s *NotificationService) TasksToday(ctx context.Context) (*notification.Message, error) {
+	upcoming, err := s.taskRepo.FetchTasksWithUpcomingDeadlines(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	overdue, err := s.taskRepo.FetchOverdueTasks(ctx, s.maxDaysOverdue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch overdue tasks: %w", err)
+	}
+	now := s.now()
+	tasks := s.targets(slices.Concat(overdue, upcoming))
+	slices.SortStableFunc(tasks, task.CompareUrgency(now))
+
+	overdueSec := notification.Section{Title: "締切超過", Color: notification.ColorRed}
+	todaySec := notification.Section{Title: "本日締切", Color: notification.ColorRed}
+	for _, t := range tasks {
+		switch {
+		case t.IsOverdue(now):
+			overdueSec.Items = append(overdueSec.Items, taskItem(t, fmt.Sprintf("⚠️ %d日超過", t.DaysOverdue(now))))
+		case t.DaysUntilDeadline(now) == 0:
+			todaySec.Items = append(todaySec.Items, taskItem(t, deadlineText(t, now, s.leadTimes.IsImminent(t, now))))
+		}
+	}
+
+	msg := &notification.Message{Title: fmt.Sprintf("📋 **今日のタスク (%s)**", formatDay(now))}
+	for _, sec := range []notification.Section{overdueSec, todaySec} {
+		if len(sec.Items) > 0 {
+			msg.Sections = append(msg.Sections, sec)
+		}
+	}
+	if len(msg.Sections) == 0 {
+		msg.Title = "✅ **今日が締切のタスクはありません**"
+	}
+	return msg, nil
+}
+
+// TasksWeek は今後 7 日以内に締切があるタスクを日別に返す。
+func (s *NotificationService) TasksWeek(ctx context.Context) (*notification.Message, error) {
+	tasks, err := s.taskRepo.FetchTasksWithUpcomingDeadlines(ctx, weekCommandDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	today := s.now()
+	var upcoming []*task.Task
+	for _, t := range s.targets(tasks) {
+		if t.IsApproachingDeadline(today, weekCommandDays) {
+			upcoming = append(upcoming, t)
+		}
+	}
+
+	if len(upcoming) == 0 {
+		return &notification.Message{Title: "✅ **今後7日間に締切のタスクはありません**"}, nil
+	}
+	return &notification.Message{
+		Title: fmt.Sprintf("🗓️ **今後%d日間のタスク (%s〜%s)**",
+			weekCommandDays, formatDay(today), formatDay(today.AddDate(0, 0, weekCommandDays))),
+		Sections: daySections(upcoming, today),
+	}, nil
+}
+
+// Reading は未完了の読書タスクの進捗を、遅れているものから順に返す。
+func (s *NotificationService) Reading(ctx context.Context) (*notification.Message, error) {
+	tasks, err := s.taskRepo.FetchIncompleteStudyTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch study tasks: %w", err)
+	}
+	tasks = s.targets(tasks)
+	if len(tasks) == 0 {
+		return &notification.Message{Title: "📚 **読書中のタスクはありません**"}, nil
+	}
+	now := s.now()
+	slices.SortStableFunc(tasks, func(a, b *task.Task) int {
+		return (b.ExpectedReadPages(now) - b.ReadPages) - (a.ExpectedReadPages(now) - a.ReadPages)
+	})
+
+	section := notification.Section{Color: notification.ColorBlue}
+	for _, t := range tasks {
+		detail := fmt.Sprintf("📖 %d%% (%d/%dページ)", t.ReadingProgress(), t.ReadPages, t.TotalPages)
+		if t.DueDate != nil {
+			detail += fmt.Sprintf(" / 1日 %dページ必要", t.RequiredPagesPerDay(now))
+		}
+		if t.IsReadingPaceDelayed(now) {
+			detail += fmt.Sprintf(" / ⚠️ 目標より %dページ遅れ", t.ExpectedReadPages(now)-t.ReadPages)
+		}
+		section.Items = append(section.Items, taskItem(t, detail))
+	}
+	return &notification.Message{
+		Title:    "📚 **読書の進捗**",
+		Sections: []notification.Section{section},
+	}, nil
+}
+
+// Snooze は query（タスク ID、またはタスク名の一部）に一致する未完了のタスクの通知を days 日止める。
+// 一致するタスクがない、または複数ある場合は通知を止めずに候補を返す。
+func (s *NotificationService) Snooze(ctx context.Context, query string, days int) (*notification.Message, error) {
+	if s.snoozeStore == nil {
+		return nil, ErrSnoozeNotConfigured
+	}
+	if days <= 0 {
+		days = defaultSnoozeDays
+	}
+
+	candidates, err := s.findTasks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(candidates) == 0:
+		return &notification.Message{Title: fmt.Sprintf("🔍 **「%s」に一致する未完了のタスクはありません**", query)}, nil
+	case len(candidates) > 1:
+		sec := notification.Section{Title: "候補", Color: notification.ColorYellow}
+		for _, t := range candidates[:min(len(candidates), maxSnoozeCandidate)] {
+			sec.Items = append(sec.Items, taskItem(t, ""))
+		}
+		return &notification.Message{
+			Title:    fmt.Sprintf("🔍 **「%s」に一致するタスクが%d件あります。名前を絞り込んでください**", query, len(candidates)),
+			Sections: []notification.Section{sec},
+		}, nil
+	}
+
+	t := candidates[0]
+	now := s.now()
+	until := now.AddDate(0, 0, days)
+	if err := s.snoozeStore.Snooze(ctx, notification.Snooze{Scope: notification.SnoozeTask, Key: t.ID, Label: t.Name, Until: until}, now); err != nil {
+		return nil, fmt.Errorf("failed to snooze task: %w", err)
+	}
+	return &notification.Message{
+		Title: fmt.Sprintf("🔕 **%s %s まで通知を止めました**", formatDay(until), until.Format("15:04")),
+		Sections: []notification.Section{{
+			Color: notification.ColorBlue,
+			Items: []notification.Item{taskItem(t, "")},
+		}},
+	}, nil
+}
+
+// findTasks は締切通知・締切超過・読書のいずれかで通知されうるタスクから query に一致するものを返す。
+// ID が完全一致するタスクがあればそれだけを返し、なければ名前の部分一致（大文字小文字を区別しない）で探す。
+func (s *NotificationService) findTasks(ctx context.Context, query string) ([]*task.Task, error) {
+	upcoming, err := s.taskRepo.FetchTasksWithUpcomingDeadlines(ctx, max(s.leadTimes.Max(), s.digestDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	overdue, err := s.taskRepo.FetchOverdueTasks(ctx, s.maxDaysOverdue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch overdue tasks: %w", err)
+	}
+	reading, err := s.taskRepo.FetchIncompleteStudyTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch study tasks: %w", err)
+	}
+
+	tasks := s.targets(slices.Concat(upcoming, overdue, reading))
+	query = strings.TrimSpace(query)
+	for _, t := range tasks {
+		if t.ID == query {
+			return []*task.Task{t}, nil
+		}
+	}
+	var matched []*task.Task
+	for _, t := range tasks {
+		if strings.Contains(strings.ToLower(t.Name), strings.ToLower(query)) {
+			matched = append(matched, t)
+		}
+	}
+	return matched, nil
+}
+
+// targets は重複と通知対象外（完了）のタスクを除き、サービスの読書ペースを設定する。
+func (s *NotificationService) targets(tasks []*task.Task) []*task.Task {
+	var result []*task.Task
+	seen := make(map[string]bool)
+	for _, t := range tasks {
+		if seen[t.ID] || !t.IsNotificationTarget() {
+			continue
+		}
+		seen[t.ID] = true
+		t.Pace = s.readingPace
+		result = append(result, t)
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+type memorySnoozeStore struct {
+	// "task:1" のように Scope と Key をつないだキー
+	entries map[string]notification.Snooze
+}
+
+func newMemorySnoozeStore() *memorySnoozeStore {
+	return &memorySnoozeStore{entries: make(map[string]notification.Snooze)}
+}
+
+func (m *memorySnoozeStore) Snooze(ctx context.Context, s notification.Snooze, now time.Time) error {
+	m.entries[string(s.Scope)+":"+s.Key] = s
+	return nil
+}
+
+func (m *memorySnoozeStore) Unsnooze(ctx context.Context, scope notification.SnoozeScope, key string) (bool, error) {
+	k := string(scope) + ":" + key
+	_, ok := m.entries[k]
+	delete(m.entries, k)
+	return ok, nil
+}
+
+func (m *memorySnoozeStore) Snoozes(ctx context.Context, now time.Time) ([]notification.Snooze, error) {
+	var active []notification.Snooze
+	for _, s := range m.entries {
+		if s.Active(now) {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+func commandTestTasks() []*task.Task {
+	newTask := func(id, name string, dueOffset int) *task.Task {
+		due := time.Date(2026, 3, 1+dueOffset, 0, 0, 0, 0, time.UTC)
+		return task.NewTask(id, name, "Work", &due, task.StatusNotStarted)
+	}
+	done := newTask("4", "完了した資料", 0)
+	done.StatusGroup = task.StatusGroupComplete
+	return []*task.Task{
+		newTask("1", "資料作成", 0),
+		newTask("2", "資料レビュー", 3),
+		newTask("3", "請求書", -2),
+		done,
+	}
+}
+
+func TestNotificationService_TasksToday(t *testing.T) {
+	repo := &mockTaskRepo{tasks: commandTestTasks()}
+	service := NewNotificationService(repo, nil, 3, WithClock(clock.Fixed(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))))
+
+	msg, err := service.TasksToday(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := msg.Text()
+	for _, want := range []string{
+		"📋 **今日のタスク (3/1 (日))**",
+		"**締切超過**\n- [Work] 請求書: ⚠️ 2日超過\n",
+		"**本日締切**\n- [Work] 資料作成: 🔴 **本日締切**\n",
+	} {
+		if !contains(text, want) {
+			t.Errorf("expected message to contain %q, got: %s", want, text)
+		}
+	}
+	if contains(text, "資料レビュー") || contains(text, "完了した資料") {
+		t.Errorf("expected only today's incomplete tasks, got: %s", text)
+	}
+}
+
+func TestNotificationService_TasksWeek(t *testing.T) {
+	repo := &mockTaskRepo{tasks: commandTestTasks()}
+	service := NewNotificationService(repo, nil, 3, WithClock(clock.Fixed(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))))
+
+	msg, err := service.TasksWeek(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := msg.Text()
+	if !contains(text, "**3/1 (日)**\n- [Work] 資料作成\n") || !contains(text, "**3/4 (水)**\n- [Work] 資料レビュー\n") {
+		t.Errorf("expected tasks grouped by day, got: %s", text)
+	}
+	if contains(text, "請求書") {
+		t.Errorf("expected overdue tasks to be excluded, got: %s", text)
+	}
+}
+
+func TestNotificationService_Snooze(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	store := newMemorySnoozeStore()
+	repo := &mockTaskRepo{tasks: commandTestTasks()}
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3,
+		WithClock(clock.Fixed(now)),
+		WithSnoozeStore(store),
+	)
+	ctx := context.Background()
+
+	// 複数に一致する場合は止めずに候補を返す
+	msg, err := service.Snooze(ctx, "資料", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(msg.Text(), "2件") || len(store.entries) != 0 {
+		t.Errorf("expected candidates without snoozing, got: %s (%v)", msg.Text(), store.entries)
+	}
+
+	msg, err = service.Snooze(ctx, "存在しない", 2)
+	if err != nil || !contains(msg.Text(), "ありません") {
+		t.Errorf("expected not found message, got: %v, %v", msg, err)
+	}
+
+	if _, err := service.Snooze(ctx, "資料作成", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if until := store.entries["task:1"].Until; !until.Equal(now.AddDate(0, 0, 2)) {
+		t.Errorf("snoozed until %v, want %v", until, now.AddDate(0, 0, 2))
+	}
+
+	// 止めたタスクは締切通知に含まれない
+	if err := service.NotifyUpcomingDeadlines(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contains(notifier.lastMessage, "資料作成") || !contains(notifier.lastMessage, "資料レビュー") {
+		t.Errorf("expected snoozed task to be skipped, got: %s", notifier.lastMessage)
+	}
+}
+
+func TestNotificationService_Snooze_NotConfigured(t *testing.T) {
+	service := NewNotificationService(&mockTaskRepo{}, nil, 3)
+	if _, err := service.Snooze(context.Background(), "資料", 1); !errors.Is(err, ErrSnoozeNotConfigured) {
+		t.Errorf("expected ErrSnoozeNotConfigured, got %v", err)
+	}
+}
@@ -2,43 +2,176 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"time"
 
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
 )
 
 type NotificationService struct {
-	taskRepo           task.Repository
-	notifier           notification.Notifier
-	daysBeforeDeadline int
+	taskRepo       task.Repository
+	channels       []notification.Channel
+	leadTimes      task.LeadTimes
+	stateStore     notification.StateStore
+	snoozeStore    notification.SnoozeStore
+	resendCooldown time.Duration
+	overdueEnabled bool
+	maxDaysOverdue int
+	digestDays     int
+	escalation     task.EscalationLadder
+	dueDayMention  string
+	readingPace    task.Pace
+	actions        *ActionConfig
+	clock          clock.Clock
+	location       *time.Location
+	// true の場合は送信済み状態を更新しない（Preview 用）
+	dryRun bool
 }
 
-func NewNotificationService(taskRepo task.Repository, notifier notification.Notifier, daysBeforeDeadline int) *NotificationService {
-	return &NotificationService{
-		taskRepo:           taskRepo,
-		notifier:           notifier,
-		daysBeforeDeadline: daysBeforeDeadline,
+type Option func(*NotificationService)
+
+// 送信済み状態を保存し、新規または緊急度が上がった通知だけを送るようにする。
+// cooldown が 0 より大きい場合は、同じ通知でもその期間が過ぎれば再送する。
+func WithStateStore(store notification.StateStore, cooldown time.Duration) Option {
+	return func(s *NotificationService) {
+		s.stateStore = store
+		s.resendCooldown = cooldown
 	}
 }
 
-func (s *NotificationService) NotifyUpcomingDeadlines(ctx context.Context) error {
-	tasks, err := s.taskRepo.FetchTasksWithUpcomingDeadlines(ctx, s.daysBeforeDeadline)
+// 通知を止めるルール（Snooze）を保存する場所を指定する。ルールに一致するタスク・プロジェクトは期限まで通知しない。
+func WithSnoozeStore(store notification.SnoozeStore) Option {
+	return func(s *NotificationService) {
+		s.snoozeStore = store
+	}
+}
+
+// 締切を過ぎた未完了タスクの通知を有効にする。maxDaysOverdue が 0 より大きい場合は
+// その日数より前に締切を過ぎたタスクを対象外にする。
+func WithOverdueAlerts(maxDaysOverdue int) Option {
+	return func(s *NotificationService) {
+		s.overdueEnabled = true
+		s.maxDaysOverdue = maxDaysOverdue
+	}
+}
+
+// 読書タスクの目標ペースを指定する。Notion でタスクごとにペースが設定されている場合はそちらを優先する。
+func WithReadingPace(p task.Pace) Option {
+	return func(s *NotificationService) {
+		s.readingPace = p
+	}
+}
+
+// 優先度ごとに締切の何日前から通知するかを指定する。指定しない優先度は daysBeforeDeadline を使う。
+func WithPriorityLeadTimes(days map[task.Priority]int) Option {
+	return func(s *NotificationService) {
+		s.leadTimes.ByPriority = maps.Clone(days)
+	}
+}
+
+// 時刻付きの締切のタスクを、締切の hours 時間前からも通知する（例: 2 時間前）。
+// 送信済み状態（WithStateStore）を使う場合、この時間帯に入ったときにもう一度通知される。
+func WithHoursBeforeDeadline(hours int) Option {
+	return func(s *NotificationService) {
+		s.leadTimes.Hours = hours
+	}
+}
+
+// 締切通知を送り直す段階を指定する。送信済み状態（WithStateStore）と組み合わせて使い、
+// タスクが次の段に進んだときだけ通知する。
+func WithEscalation(ladder task.EscalationLadder) Option {
+	return func(s *NotificationService) {
+		s.escalation = ladder
+	}
+}
+
+// 締切当日のタスクを含む締切通知に付けるメンション（例: "@here"）を指定する。
+func WithDueDayMention(mention string) Option {
+	return func(s *NotificationService) {
+		s.dueDayMention = mention
+	}
+}
+
+// 週次ダイジェストで何日先までの締切を含めるかを指定する。省略時は 7 日。
+func WithDigestDays(days int) Option {
+	return func(s *NotificationService) {
+		if days > 0 {
+			s.digestDays = days
+		}
+	}
+}
+
+// 「今日」の基準となる時計を指定する。過去の日時で通知内容を再現する場合などに使う。
+func WithClock(c clock.Clock) Option {
+	return func(s *NotificationService) {
+		s.clock = c
+	}
+}
+
+// 締切までの日数などを数えるタイムゾーン（notification.timezone）を指定する。
+// 省略時は時計（WithClock）が返す時刻のタイムゾーンで数える。
+func WithLocation(loc *time.Location) Option {
+	return func(s *NotificationService) {
+		s.location = loc
+	}
+}
+
+// daysBeforeDeadline は優先度ごとのリードタイムが指定されていないタスクを何日前から通知するか。
+func NewNotificationService(taskRepo task.Repository, channels []notification.Channel, daysBeforeDeadline int, opts ...Option) *NotificationService {
+	s := &NotificationService{
+		taskRepo:   taskRepo,
+		channels:   channels,
+		leadTimes:  task.LeadTimes{Default: daysBeforeDeadline},
+		digestDays: defaultDigestDays,
+		clock:      clock.System(nil),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// now は設定されたタイムゾーンでの現在時刻を返す。タスクの日数計算はこの時刻を基準にする。
+func (s *NotificationService) now() time.Time {
+	if s.location == nil {
+		return s.clock.Now()
+	}
+	return s.clock.Now().In(s.location)
+}
+
+// 締切通知の対象となるタスクを締切が近い順（同じ日は優先度が高い順）に返す。
+// NotifyUpcomingDeadlines と同じ条件で取得する。エスカレーションの段がリードタイムより前にある場合は、
+// その段から対象にする。
+func (s *NotificationService) UpcomingTasks(ctx context.Context) ([]*task.Task, error) {
+	tasks, err := s.taskRepo.FetchTasksWithUpcomingDeadlines(ctx, max(s.leadTimes.Max(), s.escalation.MaxDays()))
 	if err != nil {
-		return fmt.Errorf("failed to fetch tasks: %w", err)
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
 	}
 
-	if len(tasks) == 0 {
-		return nil
+	now := s.now()
+	var upcoming []*task.Task
+	for _, t := range tasks {
+		if s.leadTimes.Includes(t, now) || s.escalation.Includes(t, now) {
+			upcoming = append(upcoming, t)
+		}
 	}
+	slices.SortStableFunc(upcoming, task.CompareUrgency(now))
+	return upcoming, nil
+}
 
-	message := s.buildNotificationMessage(tasks)
-	if err := s.notifier.Notify(ctx, message); err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+func (s *NotificationService) NotifyUpcomingDeadlines(ctx context.Context) error {
+	tasks, err := s.UpcomingTasks(ctx)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return s.notify(ctx, notification.KindDeadline, tasks, s.buildNotificationMessage)
 }
 
 func (s *NotificationService) NotifyDelayedReadingTasks(ctx context.Context) error {
@@ -47,73 +180,455 @@ func (s *NotificationService) NotifyDelayedReadingTasks(ctx context.Context) err
 		return fmt.Errorf("failed to fetch study tasks: %w", err)
 	}
 
+	now := s.now()
 	var delayedTasks []*task.Task
 	for _, t := range tasks {
-		if t.IsReadingPaceDelayed() {
+		t.Pace = s.readingPace
+		if t.IsReadingPaceDelayed(now) {
 			delayedTasks = append(delayedTasks, t)
 		}
 	}
 
-	if len(delayedTasks) == 0 {
-		return nil
+	return s.notify(ctx, notification.KindReading, delayedTasks, s.buildReadingNotificationMessage)
+}
+
+func (s *NotificationService) NotifyOverdueTasks(ctx context.Context) error {
+	tasks, err := s.taskRepo.FetchOverdueTasks(ctx, s.maxDaysOverdue)
+	if err != nil {
+		return fmt.Errorf("failed to fetch overdue tasks: %w", err)
 	}
 
-	message := s.buildReadingNotificationMessage(delayedTasks)
-	if err := s.notifier.Notify(ctx, message); err != nil {
-		return fmt.Errorf("failed to send reading notification: %w", err)
+	now := s.now()
+	var overdueTasks []*task.Task
+	for _, t := range tasks {
+		if t.IsOverdue(now) && t.IsNotificationTarget() {
+			overdueTasks = append(overdueTasks, t)
+		}
 	}
 
-	return nil
+	return s.notify(ctx, notification.KindOverdue, overdueTasks, s.buildOverdueNotificationMessage)
+}
+
+type sentCounterKey struct{}
+
+// WithSentCounter は ctx を使って送信したメッセージ数（チャネルごとに 1 件）を n に加算させる。
+// ジョブの実行履歴に送信件数を残すために使う。
+func WithSentCounter(ctx context.Context, n *atomic.Int64) context.Context {
+	return context.WithValue(ctx, sentCounterKey{}, n)
+}
+
+func countSent(ctx context.Context) {
+	if n, ok := ctx.Value(sentCounterKey{}).(*atomic.Int64); ok {
+		n.Add(1)
+	}
+}
+
+type alert struct {
+	kind   notification.Kind
+	notify func(ctx context.Context) error
+}
+
+// Run で送信する通知の一覧。
+func (s *NotificationService) alerts() []alert {
+	alerts := []alert{
+		{kind: notification.KindDeadline, notify: s.NotifyUpcomingDeadlines},
+		{kind: notification.KindReading, notify: s.NotifyDelayedReadingTasks},
+	}
+	if s.overdueEnabled {
+		alerts = append(alerts, alert{kind: notification.KindOverdue, notify: s.NotifyOverdueTasks})
+	}
+	return alerts
 }
 
+// いずれかの通知が失敗しても、残りの通知は送信する。
 func (s *NotificationService) Run(ctx context.Context) error {
-	if err := s.NotifyUpcomingDeadlines(ctx); err != nil {
+	return runAlerts(ctx, s.alerts())
+}
+
+// RunKinds は kinds の通知だけを送信する。ジョブごとに送る通知を分けるために使う。
+// 明示的に指定された場合、超過通知は WithOverdueAlerts がなくても送信する。
+// 週次ダイジェストは Run には含まれず、ここで指定した場合だけ送信する。
+func (s *NotificationService) RunKinds(ctx context.Context, kinds []notification.Kind) error {
+	all := map[notification.Kind]alert{
+		notification.KindDeadline: {kind: notification.KindDeadline, notify: s.NotifyUpcomingDeadlines},
+		notification.KindReading:  {kind: notification.KindReading, notify: s.NotifyDelayedReadingTasks},
+		notification.KindOverdue:  {kind: notification.KindOverdue, notify: s.NotifyOverdueTasks},
+		notification.KindDigest:   {kind: notification.KindDigest, notify: s.NotifyWeeklyDigest},
+	}
+
+	var alerts []alert
+	for _, k := range kinds {
+		a, ok := all[k]
+		if !ok {
+			return fmt.Errorf("unknown notification kind: %s", k)
+		}
+		alerts = append(alerts, a)
+	}
+	return runAlerts(ctx, alerts)
+}
+
+func runAlerts(ctx context.Context, alerts []alert) error {
+	var errs []error
+	for _, a := range alerts {
+		errs = append(errs, a.notify(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// PreviewMessage は Run で送信されるはずのメッセージ 1 件分。
+type PreviewMessage struct {
+	Channel string
+	Kind    notification.Kind
+	Message *notification.Message
+}
+
+// Preview は Run と同じ条件でメッセージを組み立てるが、通知先には送らず返すだけにする。
+// 送信済み状態は参照するが更新しない。
+func (s *NotificationService) Preview(ctx context.Context) ([]PreviewMessage, error) {
+	var previews []PreviewMessage
+	var kind notification.Kind
+
+	dry := *s
+	dry.dryRun = true
+	dry.channels = make([]notification.Channel, len(s.channels))
+	for i, ch := range s.channels {
+		name := ch.Name
+		ch.Notifier = notifierFunc(func(ctx context.Context, msg *notification.Message) error {
+			previews = append(previews, PreviewMessage{Channel: name, Kind: kind, Message: msg})
+			return nil
+		})
+		dry.channels[i] = ch
+	}
+
+	var errs []error
+	for _, a := range dry.alerts() {
+		kind = a.kind
+		errs = append(errs, a.notify(ctx))
+	}
+	return previews, errors.Join(errs...)
+}
+
+type notifierFunc func(ctx context.Context, msg *notification.Message) error
+
+func (f notifierFunc) Notify(ctx context.Context, msg *notification.Message) error {
+	return f(ctx, msg)
+}
+
+// notify は通知を止めていない未送信の通知だけを各チャネルに送り、届いたものを送信済みとして記録する。
+func (s *NotificationService) notify(ctx context.Context, kind notification.Kind, tasks []*task.Task, build func([]*task.Task) *notification.Message) error {
+	tasks, err := s.filterSnoozed(ctx, tasks)
+	if err != nil {
+		return err
+	}
+	tasks, err = s.filterUnsent(ctx, kind, tasks)
+	if err != nil {
 		return err
 	}
-	return s.NotifyDelayedReadingTasks(ctx)
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	delivered, dispatchErr := s.dispatch(ctx, kind, tasks, build)
+	if err := s.markSent(ctx, kind, delivered); err != nil {
+		dispatchErr = errors.Join(dispatchErr, err)
+	}
+	if dispatchErr != nil {
+		return fmt.Errorf("failed to send %s notification: %w", kind, dispatchErr)
+	}
+	return nil
+}
+
+// dispatch はチャネルごとにルールに一致するタスクだけでメッセージを組み立てて送信する。
+// 失敗したチャネルがあっても残りのチャネルには送信し、失敗分を *notification.DeliveryError で返す。
+// delivered は一致したすべてのチャネルへの送信に成功したタスク。
+func (s *NotificationService) dispatch(ctx context.Context, kind notification.Kind, tasks []*task.Task, build func([]*task.Task) *notification.Message) (delivered []*task.Task, err error) {
+	now := s.now()
+	failures := make(map[string]error)
+	sent := make(map[*task.Task]bool)
+	for _, ch := range s.channels {
+		var matched []*task.Task
+		for _, t := range tasks {
+			if ch.Accepts(kind, t, severityOf(kind, t, now)) {
+				matched = append(matched, t)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		ok := true
+		if err := ch.Notifier.Notify(ctx, build(matched)); err != nil {
+			failures[ch.Name] = err
+			ok = false
+		} else {
+			countSent(ctx)
+		}
+		for _, t := range matched {
+			if prev, seen := sent[t]; !seen || prev {
+				sent[t] = ok
+			}
+		}
+	}
+
+	for _, t := range tasks {
+		if sent[t] {
+			delivered = append(delivered, t)
+		}
+	}
+	if len(failures) > 0 {
+		return delivered, &notification.DeliveryError{Failures: failures}
+	}
+	return delivered, nil
+}
+
+// filterSnoozed は通知を止めているタスク（Snooze のルールに一致するもの、Notion で通知しないにしたもの）を除く。
+func (s *NotificationService) filterSnoozed(ctx context.Context, tasks []*task.Task) ([]*task.Task, error) {
+	var snoozes []notification.Snooze
+	if s.snoozeStore != nil {
+		var err error
+		if snoozes, err = s.snoozeStore.Snoozes(ctx, s.now()); err != nil {
+			return nil, fmt.Errorf("failed to load snooze state: %w", err)
+		}
+	}
+
+	var active []*task.Task
+	for _, t := range tasks {
+		snoozed := slices.ContainsFunc(snoozes, func(sn notification.Snooze) bool { return sn.Matches(t) })
+		if !snoozed && !t.Muted {
+			active = append(active, t)
+		}
+	}
+	return active, nil
 }
 
-func (s *NotificationService) buildNotificationMessage(tasks []*task.Task) string {
-	var sb strings.Builder
-	sb.WriteString("📋 **締切が近いタスク一覧**\n\n")
+// filterUnsent は送信済み状態がないか、再送間隔を過ぎたタスクだけを返す。
+func (s *NotificationService) filterUnsent(ctx context.Context, kind notification.Kind, tasks []*task.Task) ([]*task.Task, error) {
+	if s.stateStore == nil {
+		return tasks, nil
+	}
 
+	now := s.now()
+	var unsent []*task.Task
 	for _, t := range tasks {
-		days := t.DaysUntilDeadline()
-		var dueText string
+		sentAt, ok, err := s.stateStore.LastSent(ctx, s.stateKey(kind, t, now))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load notification state: %w", err)
+		}
+		if !ok || (s.resendCooldown > 0 && now.Sub(sentAt) >= s.resendCooldown) {
+			unsent = append(unsent, t)
+		}
+	}
+	return unsent, nil
+}
+
+func (s *NotificationService) markSent(ctx context.Context, kind notification.Kind, tasks []*task.Task) error {
+	if s.stateStore == nil || s.dryRun || len(tasks) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	keys := make([]notification.StateKey, 0, len(tasks))
+	for _, t := range tasks {
+		keys = append(keys, s.stateKey(kind, t, now))
+	}
+	if err := s.stateStore.MarkSent(ctx, keys, now); err != nil {
+		return fmt.Errorf("failed to save notification state: %w", err)
+	}
+	return nil
+}
+
+// 締切通知は緊急度（エスカレーションが設定されていればその段）ごとに送信済みを記録するため、
+// 段が進むと再び通知される。締切の N 時間前の時間帯に入った場合も 1 回通知する
+// （締切日に 1 時間ごとのエスカレーションが有効な場合はそちらに従う）。
+// 締切超過は超過日数、読書は締切までの日数ごとに記録するため、状態が続く間は 1 日 1 回通知される。
+func (s *NotificationService) stateKey(kind notification.Kind, t *task.Task, now time.Time) notification.StateKey {
+	bucket := string(severityOf(kind, t, now))
+	switch kind {
+	case notification.KindOverdue:
+		bucket = fmt.Sprintf("overdue-%dd", t.DaysOverdue(now))
+	case notification.KindReading:
+		bucket = fmt.Sprintf("due-in-%dd", t.DaysUntilDeadline(now))
+	case notification.KindDeadline:
+		// 日数の段がない（締切日の 1 時間ごとだけの）場合、締切日より前は緊急度で区切る
+		if rung := s.escalation.Rung(t, now); rung != "" {
+			bucket = rung
+		}
+		hourly := s.escalation.HourlyOnDueDay && t.DaysUntilDeadline(now) == 0
+		if s.leadTimes.IsImminent(t, now) && !hourly {
+			bucket = fmt.Sprintf("within-%dh", s.leadTimes.Hours)
+		}
+	}
+	return notification.StateKey{
+		TaskID: t.ID,
+		Kind:   kind,
+		Bucket: bucket,
+	}
+}
+
+// 緊急度は締切通知にのみ付く。
+func severityOf(kind notification.Kind, t *task.Task, now time.Time) notification.Severity {
+	if kind != notification.KindDeadline {
+		return ""
+	}
+	return notification.SeverityOf(t.DaysUntilDeadline(now))
+}
+
+// 締切までの日数で本日・明日・それ以降のセクションに分け、緊急度に応じた色を付ける。
+// 時刻付きの締切で N 時間前（WithHoursBeforeDeadline）に入ったタスクは先頭のセクションにまとめる。
+func (s *NotificationService) buildNotificationMessage(tasks []*task.Task) *notification.Message {
+	imminent := notification.Section{Title: fmt.Sprintf("%d時間以内に締切", s.leadTimes.Hours), Color: notification.ColorRed}
+	today := notification.Section{Title: "本日締切", Color: notification.ColorRed}
+	tomorrow := notification.Section{Title: "明日締切", Color: notification.ColorOrange}
+	later := notification.Section{Title: "近日締切", Color: notification.ColorYellow}
+
+	now := s.now()
+	sorted := slices.Clone(tasks)
+	slices.SortStableFunc(sorted, task.CompareUrgency(now))
+	for _, t := range sorted {
+		days := t.DaysUntilDeadline(now)
+		isImminent := s.leadTimes.IsImminent(t, now)
+		dueText := deadlineText(t, now, isImminent)
+		if label := priorityLabel(t.Priority); label != "" {
+			dueText += " / " + label
+		}
+
+		item := taskItem(t, dueText)
+		item.Actions = s.taskActions(t, ActionDone, ActionPostpone)
 		switch {
+		case isImminent:
+			imminent.Items = append(imminent.Items, item)
 		case days == 0:
-			dueText = "🔴 **本日締切**"
+			today.Items = append(today.Items, item)
 		case days == 1:
-			dueText = "🟠 明日締切"
+			tomorrow.Items = append(tomorrow.Items, item)
 		default:
-			dueText = fmt.Sprintf("🟡 あと%d日", days)
+			later.Items = append(later.Items, item)
 		}
+	}
+
+	msg := &notification.Message{Title: "📋 **締切が近いタスク一覧**"}
+	if len(imminent.Items) > 0 || len(today.Items) > 0 {
+		msg.Mention = s.dueDayMention
+	}
+	for _, sec := range []notification.Section{imminent, today, tomorrow, later} {
+		if len(sec.Items) > 0 {
+			msg.Sections = append(msg.Sections, sec)
+		}
+	}
+	return msg
+}
 
-		projectNameStr := ""
-		if t.ProjectName != "" {
-			projectNameStr = fmt.Sprintf("[%s] ", t.ProjectName)
+func (s *NotificationService) buildReadingNotificationMessage(tasks []*task.Task) *notification.Message {
+	now := s.now()
+	section := notification.Section{Color: notification.ColorBlue}
+	for _, t := range tasks {
+		expected := t.ExpectedReadPages(now)
+		diff := expected - t.ReadPages
+		detail := fmt.Sprintf("現在 %dページ / 目標 %dページ (残り: %dp) / 1日 %dページ必要",
+			t.ReadPages, expected, diff, t.RequiredPagesPerDay(now))
+		if finish := t.ProjectedFinishDate(now); finish != nil {
+			detail += fmt.Sprintf(" / 今のペースだと %s 読了見込み", finish.Format("1/2"))
 		}
+		item := taskItem(t, detail)
+		item.Actions = s.taskActions(t, ActionAddPages, ActionDone)
+		section.Items = append(section.Items, item)
+	}
 
-		sb.WriteString(fmt.Sprintf("- %s%s: %s\n", projectNameStr, t.Name, dueText))
+	return &notification.Message{
+		Title:    "📚 **読書ペース遅延アラート**",
+		Sections: []notification.Section{section},
 	}
+}
+
+// 締切を過ぎた日数が大きい順（同じ日数は優先度が高い順）に並べる。
+func (s *NotificationService) buildOverdueNotificationMessage(tasks []*task.Task) *notification.Message {
+	now := s.now()
+	sorted := slices.Clone(tasks)
+	slices.SortStableFunc(sorted, task.CompareUrgency(now))
 
-	return sb.String()
+	section := notification.Section{Color: notification.ColorRed}
+	for _, t := range sorted {
+		item := taskItem(t, fmt.Sprintf("⚠️ %d日超過", t.DaysOverdue(now)))
+		item.Actions = s.taskActions(t, ActionDone, ActionPostpone)
+		section.Items = append(section.Items, item)
+	}
+
+	return &notification.Message{
+		Title:    "⏰ **締切を過ぎたタスク一覧**",
+		Sections: []notification.Section{section},
+	}
 }
 
-func (s *NotificationService) buildReadingNotificationMessage(tasks []*task.Task) string {
-	var sb strings.Builder
-	sb.WriteString("📚 **読書ペース遅延アラート**\n\n")
+// deadlineText は締切までの日数（時刻付きの場合は締切時刻も）を表す。
+//   - "🔴 **本日締切**", "🔴 **本日 18:00 締切**", "🔴 **本日 10:00 締切 (時刻超過)**"
+//   - "🟠 明日 18:00 締切", "🟡 あと3日 (3/5 18:00 締切)"
+//   - "⏰ **あと1時間30分 (18:00 締切)**"（imminent の場合）
+func deadlineText(t *task.Task, now time.Time, imminent bool) string {
+	days := t.DaysUntilDeadline(now)
+	at := dueClock(t)
+	if imminent {
+		remaining, _ := t.TimeUntilDeadline(now)
+		return fmt.Sprintf("⏰ **あと%s (%s 締切)**", formatRemaining(remaining), at)
+	}
 
-	for _, t := range tasks {
-		expected := t.ExpectedReadPages()
-		diff := expected - t.ReadPages
-		projectNameStr := ""
-		if t.ProjectName != "" {
-			projectNameStr = fmt.Sprintf("[%s] ", t.ProjectName)
+	switch {
+	case days == 0 && at == "":
+		return "🔴 **本日締切**"
+	case days == 0:
+		if remaining, _ := t.TimeUntilDeadline(now); remaining < 0 {
+			return fmt.Sprintf("🔴 **本日 %s 締切 (時刻超過)**", at)
 		}
-		sb.WriteString(fmt.Sprintf("- %s%s: 現在 %dページ / 目標 %dページ (残り: %dp)\n",
-			projectNameStr, t.Name, t.ReadPages, expected, diff))
+		return fmt.Sprintf("🔴 **本日 %s 締切**", at)
+	case days == 1 && at == "":
+		return "🟠 明日締切"
+	case days == 1:
+		return fmt.Sprintf("🟠 明日 %s 締切", at)
+	case at == "":
+		return fmt.Sprintf("🟡 あと%d日", days)
+	default:
+		return fmt.Sprintf("🟡 あと%d日 (%s %s 締切)", days, t.DueDate.Format("1/2"), at)
 	}
+}
+
+// dueClock は締切時刻を "18:00" の形式で返す。締切が日付のみの場合は ""。
+func dueClock(t *task.Task) string {
+	if t.DueDate == nil || !t.DueHasTime {
+		return ""
+	}
+	return t.DueDate.Format("15:04")
+}
 
-	return sb.String()
+// formatRemaining は残り時間を "2時間", "1時間30分", "45分" の形式で返す。1 分未満は切り上げる。
+func formatRemaining(d time.Duration) string {
+	minutes := int((max(d, 0) + time.Minute - 1) / time.Minute)
+	switch h, m := minutes/60, minutes%60; {
+	case h == 0:
+		return fmt.Sprintf("%d分", m)
+	case m == 0:
+		return fmt.Sprintf("%d時間", h)
+	default:
+		return fmt.Sprintf("%d時間%d分", h, m)
+	}
+}
+
+func priorityLabel(p task.Priority) string {
+	switch p {
+	case task.PriorityHigh:
+		return "優先度: 高"
+	case task.PriorityMedium:
+		return "優先度: 中"
+	case task.PriorityLow:
+		return "優先度: 低"
+	default:
+		return ""
+	}
+}
+
+func taskItem(t *task.Task, detail string) notification.Item {
+	return notification.Item{
+		Name:    t.Name,
+		URL:     t.URL,
+		Project: t.ProjectName,
+		Detail:  detail,
+	}
 }
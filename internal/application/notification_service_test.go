@@ -2,18 +2,29 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
 )
 
 type mockTaskRepo struct {
 	tasks []*task.Task
 	err   error
+	// 更新操作の記録。例: "1 status=Done"
+	updates []string
+	// FetchTasksWithUpcomingDeadlines に渡された日数
+	lastDays int
 }
 
 func (m *mockTaskRepo) FetchTasksWithUpcomingDeadlines(ctx context.Context, days int) ([]*task.Task, error) {
+	m.lastDays = days
 	return m.tasks, m.err
 }
 
@@ -21,16 +32,48 @@ func (m *mockTaskRepo) FetchIncompleteStudyTasks(ctx context.Context) ([]*task.T
 	return m.tasks, m.err
 }
 
+func (m *mockTaskRepo) FetchOverdueTasks(ctx context.Context, maxDaysOverdue int) ([]*task.Task, error) {
+	return m.tasks, m.err
+}
+
+func (m *mockTaskRepo) FetchTask(ctx context.Context, taskID string) (*task.Task, error) {
+	for _, t := range m.tasks {
+		if t.ID == taskID {
+			return t, m.err
+		}
+	}
+	return nil, fmt.Errorf("task %s not found", taskID)
+}
+
+func (m *mockTaskRepo) UpdateStatus(ctx context.Context, taskID string, status task.Status) error {
+	m.updates = append(m.updates, fmt.Sprintf("%s status=%s", taskID, status))
+	return m.err
+}
+
+func (m *mockTaskRepo) UpdateReadPages(ctx context.Context, taskID string, readPages int) error {
+	m.updates = append(m.updates, fmt.Sprintf("%s read_pages=%d", taskID, readPages))
+	return m.err
+}
+
+func (m *mockTaskRepo) PostponeDue(ctx context.Context, taskID string, due time.Time, hasTime bool) error {
+	m.updates = append(m.updates, fmt.Sprintf("%s due=%s has_time=%v", taskID, due.Format(time.RFC3339), hasTime))
+	return m.err
+}
+
 type mockNotifier struct {
 	lastMessage string
 	err         error
 }
 
-func (m *mockNotifier) Notify(ctx context.Context, message string) error {
-	m.lastMessage = message
+func (m *mockNotifier) Notify(ctx context.Context, message *notification.Message) error {
+	m.lastMessage = message.Text()
 	return m.err
 }
 
+func singleChannel(n notification.Notifier) []notification.Channel {
+	return []notification.Channel{{Name: "default", Notifier: n}}
+}
+
 func TestNotificationService_NotifyUpcomingDeadlines(t *testing.T) {
 	today := time.Now().Truncate(24 * time.Hour)
 	tomorrow := today.Add(24 * time.Hour)
@@ -42,7 +85,7 @@ func TestNotificationService_NotifyUpcomingDeadlines(t *testing.T) {
 
 	repo := &mockTaskRepo{tasks: tasks}
 	notifier := &mockNotifier{}
-	service := NewNotificationService(repo, notifier, 3)
+	service := NewNotificationService(repo, singleChannel(notifier), 3)
 
 	err := service.NotifyUpcomingDeadlines(context.Background())
 	if err != nil {
@@ -61,10 +104,39 @@ func TestNotificationService_NotifyUpcomingDeadlines(t *testing.T) {
 	}
 }
 
+func TestNotificationService_buildNotificationMessage_Severity(t *testing.T) {
+	today := time.Now()
+	tomorrow := today.AddDate(0, 0, 1)
+	later := today.AddDate(0, 0, 3)
+
+	tasks := []*task.Task{
+		task.NewTask("1", "Later", "Work", &later, task.StatusNotStarted),
+		task.NewTask("2", "Today", "Work", &today, task.StatusNotStarted),
+		task.NewTask("3", "Tomorrow", "Work", &tomorrow, task.StatusNotStarted),
+	}
+
+	service := NewNotificationService(&mockTaskRepo{}, singleChannel(&mockNotifier{}), 3)
+	msg := service.buildNotificationMessage(tasks)
+
+	wantColors := []notification.Color{notification.ColorRed, notification.ColorOrange, notification.ColorYellow}
+	wantNames := []string{"Today", "Tomorrow", "Later"}
+	if len(msg.Sections) != len(wantColors) {
+		t.Fatalf("expected %d sections, got %d", len(wantColors), len(msg.Sections))
+	}
+	for i, sec := range msg.Sections {
+		if sec.Color != wantColors[i] {
+			t.Errorf("sections[%d].Color = %#x, want %#x", i, sec.Color, wantColors[i])
+		}
+		if len(sec.Items) != 1 || sec.Items[0].Name != wantNames[i] {
+			t.Errorf("sections[%d] items = %+v, want %s", i, sec.Items, wantNames[i])
+		}
+	}
+}
+
 func TestNotificationService_NoTasks(t *testing.T) {
 	repo := &mockTaskRepo{tasks: []*task.Task{}}
 	notifier := &mockNotifier{}
-	service := NewNotificationService(repo, notifier, 3)
+	service := NewNotificationService(repo, singleChannel(notifier), 3)
 
 	err := service.NotifyUpcomingDeadlines(context.Background())
 	if err != nil {
@@ -76,6 +148,589 @@ func TestNotificationService_NoTasks(t *testing.T) {
 	}
 }
 
+func TestNotificationService_Routing(t *testing.T) {
+	today := time.Now()
+	later := today.AddDate(0, 0, 3)
+
+	workToday := task.NewTask("1", "Work Today", "Work", &today, task.StatusInProgress)
+	workLater := task.NewTask("2", "Work Later", "Work", &later, task.StatusInProgress)
+	personalToday := task.NewTask("3", "Personal Today", "Personal", &today, task.StatusNotStarted)
+
+	team := &mockNotifier{}
+	urgent := &mockNotifier{}
+	reading := &mockNotifier{}
+	channels := []notification.Channel{
+		{
+			Name:     "team",
+			Notifier: team,
+			Rules:    []notification.Rule{{Kinds: []notification.Kind{notification.KindDeadline}, Projects: []string{"Work"}}},
+		},
+		{
+			Name:     "urgent",
+			Notifier: urgent,
+			Rules:    []notification.Rule{{Severities: []notification.Severity{notification.SeverityToday}}},
+		},
+		{
+			Name:     "reading",
+			Notifier: reading,
+			Rules:    []notification.Rule{{Kinds: []notification.Kind{notification.KindReading}}},
+		},
+	}
+
+	repo := &mockTaskRepo{tasks: []*task.Task{workToday, workLater, personalToday}}
+	service := NewNotificationService(repo, channels, 3)
+
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !contains(team.lastMessage, "Work Today") || !contains(team.lastMessage, "Work Later") {
+		t.Errorf("team channel should receive both Work tasks, got: %s", team.lastMessage)
+	}
+	if contains(team.lastMessage, "Personal Today") {
+		t.Errorf("team channel should not receive Personal tasks, got: %s", team.lastMessage)
+	}
+	if !contains(urgent.lastMessage, "Work Today") || !contains(urgent.lastMessage, "Personal Today") {
+		t.Errorf("urgent channel should receive today's tasks, got: %s", urgent.lastMessage)
+	}
+	if contains(urgent.lastMessage, "Work Later") {
+		t.Errorf("urgent channel should not receive later tasks, got: %s", urgent.lastMessage)
+	}
+	if reading.lastMessage != "" {
+		t.Errorf("reading channel should not receive deadline alerts, got: %s", reading.lastMessage)
+	}
+}
+
+func TestNotificationService_Routing_ReportsFailedChannels(t *testing.T) {
+	today := time.Now()
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Task", "Work", &today, task.StatusInProgress),
+	}}
+
+	ok := &mockNotifier{}
+	channels := []notification.Channel{
+		{Name: "broken-a", Notifier: &mockNotifier{err: errors.New("boom")}},
+		{Name: "ok", Notifier: ok},
+		{Name: "broken-b", Notifier: &mockNotifier{err: errors.New("boom")}},
+	}
+	service := NewNotificationService(repo, channels, 3)
+
+	err := service.NotifyUpcomingDeadlines(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var deliveryErr *notification.DeliveryError
+	if !errors.As(err, &deliveryErr) {
+		t.Fatalf("expected DeliveryError, got %T", err)
+	}
+	failed := deliveryErr.FailedChannels()
+	if len(failed) != 2 || failed[0] != "broken-a" || failed[1] != "broken-b" {
+		t.Errorf("unexpected failed channels: %v", failed)
+	}
+	if ok.lastMessage == "" {
+		t.Error("healthy channel should still be notified")
+	}
+}
+
+func TestNotificationService_NotifyOverdueTasks(t *testing.T) {
+	twoDaysAgo := time.Now().AddDate(0, 0, -2)
+	fiveDaysAgo := time.Now().AddDate(0, 0, -5)
+	today := time.Now()
+
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Slightly Late", "Work", &twoDaysAgo, task.StatusInProgress),
+		task.NewTask("2", "Very Late", "Personal", &fiveDaysAgo, task.StatusNotStarted),
+		task.NewTask("3", "Due Today", "Work", &today, task.StatusInProgress),
+		task.NewTask("4", "Done Late", "Work", &fiveDaysAgo, task.StatusDone),
+	}}
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithOverdueAlerts(30))
+
+	if err := service.NotifyOverdueTasks(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "⏰ **締切を過ぎたタスク一覧**\n\n" +
+		"- [Personal] Very Late: ⚠️ 5日超過\n" +
+		"- [Work] Slightly Late: ⚠️ 2日超過\n"
+	if notifier.lastMessage != want {
+		t.Errorf("unexpected message:\n%s\nwant:\n%s", notifier.lastMessage, want)
+	}
+}
+
+func TestNotificationService_NotifyDelayedReadingTasks(t *testing.T) {
+	now := time.Now()
+	due := now.AddDate(0, 0, 4)
+	start := now.AddDate(0, 0, -4)
+
+	// 開始日〜締切の 9 日間で 90 ページ、今日が 5 日目なので目標 50 ページ
+	book := &task.Task{
+		ID:          "1",
+		Name:        "Book",
+		ProjectName: "Study",
+		TaskType:    "Study",
+		Status:      task.StatusInProgress,
+		StartDate:   &start,
+		DueDate:     &due,
+		TotalPages:  90,
+		ReadPages:   20,
+	}
+	repo := &mockTaskRepo{tasks: []*task.Task{book}}
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithReadingPace(task.Pace{Mode: task.PaceLinear}))
+
+	if err := service.NotifyDelayedReadingTasks(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 残り 70 ページを 5 日（今日〜締切）で読むには 1 日 14 ページ、実績 1 日 4 ページだと 18 日後
+	finish := now.AddDate(0, 0, 18).Format("1/2")
+	want := "- [Study] Book: 現在 20ページ / 目標 50ページ (残り: 30p) / 1日 14ページ必要 / 今のペースだと " + finish + " 読了見込み"
+	if !contains(notifier.lastMessage, want) {
+		t.Errorf("expected message to contain %q, got: %s", want, notifier.lastMessage)
+	}
+}
+
+func TestNotificationService_FixedClock(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, jst)
+
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Report", "Work", &due, task.StatusInProgress),
+	}}
+	notifier := &mockNotifier{}
+	// 時計が UTC を返しても、日付は設定されたタイムゾーン（JST）で数える
+	now := clock.Fixed(time.Date(2026, 2, 28, 14, 30, 0, 0, time.UTC))
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithClock(now), WithLocation(jst))
+
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(notifier.lastMessage, "明日締切") {
+		t.Errorf("expected '明日締切' as of 2026-02-28, got: %s", notifier.lastMessage)
+	}
+}
+
+func TestNotificationService_PriorityLeadTimesAndOrder(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newTask := func(id string, dueOffset int, priority task.Priority) *task.Task {
+		due := time.Date(2026, 3, 1+dueOffset, 0, 0, 0, 0, time.UTC)
+		t := task.NewTask(id, id, "Work", &due, task.StatusNotStarted)
+		t.Priority = priority
+		return t
+	}
+
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		newTask("low-in-2-days", 2, task.PriorityLow),
+		newTask("high-in-6-days", 6, task.PriorityHigh),
+		newTask("none-in-2-days", 2, task.PriorityNone),
+		newTask("high-in-2-days", 2, task.PriorityHigh),
+		newTask("none-in-5-days", 5, task.PriorityNone),
+	}}
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3,
+		WithClock(clock.Fixed(now)),
+		WithPriorityLeadTimes(map[task.Priority]int{task.PriorityHigh: 7, task.PriorityLow: 1}),
+	)
+
+	tasks, err := service.UpcomingTasks(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, t := range tasks {
+		got = append(got, t.ID)
+	}
+	// low は 1 日前から、high は 7 日前から、それ以外は 3 日前から通知する
+	want := []string{"high-in-2-days", "none-in-2-days", "high-in-6-days"}
+	if !slices.Equal(got, want) {
+		t.Errorf("UpcomingTasks() = %v, want %v", got, want)
+	}
+
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(notifier.lastMessage, "- [Work] high-in-2-days: 🟡 あと2日 / 優先度: 高\n- [Work] none-in-2-days: 🟡 あと2日\n") {
+		t.Errorf("expected tasks ordered by days left then priority, got: %s", notifier.lastMessage)
+	}
+}
+
+func TestNotificationService_Escalation(t *testing.T) {
+	due := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	store := newMemoryStateStore()
+	ladder := task.EscalationLadder{Days: []int{7, 3, 1, 0}, HourlyOnDueDay: true}
+
+	steps := []struct {
+		now      time.Time
+		wantSent bool
+	}{
+		{now: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), wantSent: true},    // 7 日前の段に入る
+		{now: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), wantSent: false},   // 5 日前: まだ 7 日前の段
+		{now: time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC), wantSent: true},    // 3 日前の段
+		{now: time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC), wantSent: false},   // 2 日前: まだ 3 日前の段
+		{now: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), wantSent: true},   // 締切日、残り 9 時間
+		{now: time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), wantSent: false}, // 同じ 1 時間のうち
+		{now: time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC), wantSent: true},  // 残り 8 時間
+	}
+
+	for i, step := range steps {
+		tk := task.NewTask("1", "Report", "Work", &due, task.StatusInProgress)
+		tk.DueHasTime = true
+		repo := &mockTaskRepo{tasks: []*task.Task{tk}}
+		notifier := &mockNotifier{}
+		service := NewNotificationService(repo, singleChannel(notifier), 7,
+			WithClock(clock.Fixed(step.now)),
+			WithStateStore(store, 0),
+			WithEscalation(ladder),
+			WithDueDayMention("@here"),
+		)
+
+		if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if sent := notifier.lastMessage != ""; sent != step.wantSent {
+			t.Errorf("step %d (%s): sent = %v, want %v", i, step.now, sent, step.wantSent)
+		}
+		if dueDay := step.now.Day() == due.Day(); notifier.lastMessage != "" && contains(notifier.lastMessage, "@here") != dueDay {
+			t.Errorf("step %d: expected @here only on the due day, got: %s", i, notifier.lastMessage)
+		}
+	}
+}
+
+func TestNotificationService_Escalation_BeyondLeadTime(t *testing.T) {
+	due := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	store := newMemoryStateStore()
+	ladder := task.EscalationLadder{Days: []int{7, 3, 1, 0}}
+
+	steps := []struct {
+		now      time.Time
+		wantSent bool
+	}{
+		{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), wantSent: false}, // 9 日前: 段より前
+		{now: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), wantSent: true},  // 7 日前の段（days_before の 3 日より前）
+		{now: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), wantSent: false}, // まだ 7 日前の段
+		{now: time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC), wantSent: true},  // 3 日前の段
+	}
+
+	for i, step := range steps {
+		repo := &mockTaskRepo{tasks: []*task.Task{task.NewTask("1", "Report", "Work", &due, task.StatusInProgress)}}
+		notifier := &mockNotifier{}
+		service := NewNotificationService(repo, singleChannel(notifier), 3,
+			WithClock(clock.Fixed(step.now)),
+			WithStateStore(store, 0),
+			WithEscalation(ladder),
+		)
+
+		if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if sent := notifier.lastMessage != ""; sent != step.wantSent {
+			t.Errorf("step %d (%s): sent = %v, want %v", i, step.now, sent, step.wantSent)
+		}
+		if step.wantSent && repo.lastDays < 7 {
+			t.Errorf("step %d: fetched %d days ahead, want at least 7", i, repo.lastDays)
+		}
+	}
+}
+
+func TestNotificationService_Escalation_HourlyOnly(t *testing.T) {
+	due := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	store := newMemoryStateStore()
+	ladder := task.EscalationLadder{HourlyOnDueDay: true}
+
+	steps := []struct {
+		now      time.Time
+		wantSent bool
+	}{
+		{now: time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC), wantSent: true},   // 3 日前
+		{now: time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC), wantSent: false},  // 2 日前: 緊急度は変わらない
+		{now: time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), wantSent: true},   // 明日締切
+		{now: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), wantSent: true},  // 締切日、残り 9 時間
+		{now: time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC), wantSent: true}, // 残り 8 時間
+	}
+
+	for i, step := range steps {
+		tk := task.NewTask("1", "Report", "Work", &due, task.StatusInProgress)
+		tk.DueHasTime = true
+		repo := &mockTaskRepo{tasks: []*task.Task{tk}}
+		notifier := &mockNotifier{}
+		service := NewNotificationService(repo, singleChannel(notifier), 7,
+			WithClock(clock.Fixed(step.now)),
+			WithStateStore(store, 0),
+			WithEscalation(ladder),
+		)
+
+		if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if sent := notifier.lastMessage != ""; sent != step.wantSent {
+			t.Errorf("step %d (%s): sent = %v, want %v", i, step.now, sent, step.wantSent)
+		}
+	}
+}
+
+func TestNotificationService_TimeOfDay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newTask := func(id string, due time.Time, hasTime bool) *task.Task {
+		t := task.NewTask(id, id, "Work", &due, task.StatusNotStarted)
+		t.DueHasTime = hasTime
+		return t
+	}
+
+	tasks := []*task.Task{
+		newTask("date-only", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), false),
+		newTask("evening", time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), true),
+		newTask("morning", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), true),
+		newTask("soon", time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC), true),
+		newTask("tomorrow", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), true),
+		newTask("later", time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), true),
+	}
+	service := NewNotificationService(&mockTaskRepo{}, singleChannel(&mockNotifier{}), 3,
+		WithClock(clock.Fixed(now)),
+		WithHoursBeforeDeadline(2),
+	)
+	text := service.buildNotificationMessage(tasks).Text()
+
+	for _, want := range []string{
+		"**2時間以内に締切**\n- [Work] soon: ⏰ **あと1時間30分 (10:30 締切)**\n",
+		"- [Work] date-only: 🔴 **本日締切**\n- [Work] morning: 🔴 **本日 08:00 締切 (時刻超過)**\n- [Work] evening: 🔴 **本日 18:00 締切**\n",
+		"- [Work] tomorrow: 🟠 明日 09:00 締切\n",
+		"- [Work] later: 🟡 あと3日 (3/4 12:00 締切)\n",
+	} {
+		if !contains(text, want) {
+			t.Errorf("expected message to contain %q, got: %s", want, text)
+		}
+	}
+}
+
+func TestNotificationService_HoursBeforeDeadline(t *testing.T) {
+	due := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+	store := newMemoryStateStore()
+
+	steps := []struct {
+		now      time.Time
+		wantSent bool
+	}{
+		{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), wantSent: true},   // 明日締切
+		{now: time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC), wantSent: false}, // 明日締切のまま
+		{now: time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC), wantSent: true}, // 締切の 2 時間前に入った（日付は明日のまま）
+		{now: time.Date(2026, 3, 2, 0, 30, 0, 0, time.UTC), wantSent: false}, // 2 時間前の通知は送信済み
+	}
+
+	for i, step := range steps {
+		tk := task.NewTask("1", "Report", "Work", &due, task.StatusInProgress)
+		tk.DueHasTime = true
+		notifier := &mockNotifier{}
+		service := NewNotificationService(&mockTaskRepo{tasks: []*task.Task{tk}}, singleChannel(notifier), 1,
+			WithClock(clock.Fixed(step.now)),
+			WithStateStore(store, 0),
+			WithHoursBeforeDeadline(2),
+		)
+
+		if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if sent := notifier.lastMessage != ""; sent != step.wantSent {
+			t.Errorf("step %d (%s): sent = %v, want %v", i, step.now, sent, step.wantSent)
+		}
+	}
+}
+
+func TestNotificationService_Preview(t *testing.T) {
+	today := time.Now()
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Report", "Work", &today, task.StatusInProgress),
+	}}
+	store := newMemoryStateStore()
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithStateStore(store, 0))
+
+	previews, err := service.Preview(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if notifier.lastMessage != "" {
+		t.Errorf("preview should not send notifications, got: %s", notifier.lastMessage)
+	}
+	if len(store.records) != 0 {
+		t.Errorf("preview should not mark alerts as sent: %v", store.records)
+	}
+	if len(previews) != 1 {
+		t.Fatalf("expected 1 preview, got %d", len(previews))
+	}
+	if previews[0].Channel != "default" || previews[0].Kind != notification.KindDeadline {
+		t.Errorf("unexpected preview: %+v", previews[0])
+	}
+	if !contains(previews[0].Message.Text(), "Report") {
+		t.Errorf("expected preview to contain 'Report', got: %s", previews[0].Message.Text())
+	}
+}
+
+func TestNotificationService_RunKinds(t *testing.T) {
+	past := time.Now().AddDate(0, 0, -2)
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Late Report", "Work", &past, task.StatusInProgress),
+	}}
+	notifier := &mockNotifier{}
+	// WithOverdueAlerts なしでも、明示的に指定すれば超過通知を送る
+	service := NewNotificationService(repo, singleChannel(notifier), 3)
+
+	if err := service.RunKinds(context.Background(), []notification.Kind{notification.KindOverdue}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(notifier.lastMessage, "締切を過ぎたタスク一覧") || !contains(notifier.lastMessage, "Late Report") {
+		t.Errorf("expected overdue message, got: %s", notifier.lastMessage)
+	}
+
+	var sent atomic.Int64
+	ctx := WithSentCounter(context.Background(), &sent)
+	if err := service.RunKinds(ctx, []notification.Kind{notification.KindOverdue}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.Load() != 1 {
+		t.Errorf("expected 1 sent message, got %d", sent.Load())
+	}
+
+	if err := service.RunKinds(context.Background(), []notification.Kind{"unknown"}); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+type memoryStateStore struct {
+	records map[notification.StateKey]time.Time
+}
+
+func newMemoryStateStore() *memoryStateStore {
+	return &memoryStateStore{records: make(map[notification.StateKey]time.Time)}
+}
+
+func (m *memoryStateStore) LastSent(ctx context.Context, key notification.StateKey) (time.Time, bool, error) {
+	t, ok := m.records[key]
+	return t, ok, nil
+}
+
+func (m *memoryStateStore) MarkSent(ctx context.Context, keys []notification.StateKey, sentAt time.Time) error {
+	for _, k := range keys {
+		m.records[k] = sentAt
+	}
+	return nil
+}
+
+func TestNotificationService_Deduplication(t *testing.T) {
+	later := time.Now().AddDate(0, 0, 3)
+	target := task.NewTask("1", "Task", "Work", &later, task.StatusInProgress)
+	repo := &mockTaskRepo{tasks: []*task.Task{target}}
+	store := newMemoryStateStore()
+
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithStateStore(store, 0))
+
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(notifier.lastMessage, "Task") {
+		t.Fatalf("expected first run to notify, got: %s", notifier.lastMessage)
+	}
+
+	notifier.lastMessage = ""
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notifier.lastMessage != "" {
+		t.Errorf("expected same alert not to be re-sent, got: %s", notifier.lastMessage)
+	}
+
+	// 明日締切に上がったら再度通知する
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	target.DueDate = &tomorrow
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(notifier.lastMessage, "明日締切") {
+		t.Errorf("expected escalated alert to be sent, got: %s", notifier.lastMessage)
+	}
+}
+
+func TestNotificationService_Deduplication_Cooldown(t *testing.T) {
+	later := time.Now().AddDate(0, 0, 3)
+	target := task.NewTask("1", "Task", "Work", &later, task.StatusInProgress)
+	repo := &mockTaskRepo{tasks: []*task.Task{target}}
+
+	store := newMemoryStateStore()
+	key := notification.StateKey{TaskID: "1", Kind: notification.KindDeadline, Bucket: string(notification.SeverityLater)}
+
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithStateStore(store, 6*time.Hour))
+
+	store.records[key] = time.Now().Add(-time.Hour)
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notifier.lastMessage != "" {
+		t.Errorf("expected no re-send within cooldown, got: %s", notifier.lastMessage)
+	}
+
+	store.records[key] = time.Now().Add(-7 * time.Hour)
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notifier.lastMessage == "" {
+		t.Error("expected re-send after cooldown")
+	}
+}
+
+func TestNotificationService_Deduplication_OverdueDaily(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := newMemoryStateStore()
+
+	steps := []struct {
+		now      time.Time
+		wantSent bool
+	}{
+		{now: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), wantSent: true},   // 1日超過
+		{now: time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC), wantSent: false}, // 同じ日
+		{now: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), wantSent: true},   // 2日超過
+	}
+	for i, step := range steps {
+		tk := task.NewTask("1", "請求書", "Work", &due, task.StatusNotStarted)
+		notifier := &mockNotifier{}
+		service := NewNotificationService(&mockTaskRepo{tasks: []*task.Task{tk}}, singleChannel(notifier), 3,
+			WithClock(clock.Fixed(step.now)),
+			WithStateStore(store, 0),
+			WithOverdueAlerts(0),
+		)
+		if err := service.NotifyOverdueTasks(context.Background()); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if sent := notifier.lastMessage != ""; sent != step.wantSent {
+			t.Errorf("step %d (%s): sent = %v, want %v", i, step.now, sent, step.wantSent)
+		}
+	}
+}
+
+func TestNotificationService_Deduplication_FailedDeliveryIsNotMarked(t *testing.T) {
+	today := time.Now()
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Task", "Work", &today, task.StatusInProgress),
+	}}
+	store := newMemoryStateStore()
+
+	notifier := &mockNotifier{err: errors.New("boom")}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithStateStore(store, 0))
+
+	if err := service.NotifyUpcomingDeadlines(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(store.records) != 0 {
+		t.Errorf("failed delivery should not be marked as sent: %v", store.records)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
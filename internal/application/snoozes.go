@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+// 通知を止めるルールの管理（CLI の snooze コマンドと HTTP API から使う）。
+
+// ErrInvalidSnooze はルールの対象や期限が正しくない場合に返す。
+var ErrInvalidSnooze = errors.New("invalid snooze")
+
+// ListSnoozes は現在有効なルールを返す。
+func (s *NotificationService) ListSnoozes(ctx context.Context) ([]notification.Snooze, error) {
+	if s.snoozeStore == nil {
+		return nil, ErrSnoozeNotConfigured
+	}
+	snoozes, err := s.snoozeStore.Snoozes(ctx, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snoozes: %w", err)
+	}
+	return snoozes, nil
+}
+
+// AddSnooze はルールを保存する。同じ対象のルールがある場合は期限を上書きする。
+// Until がゼロ値の場合は解除するまで通知を止める。保存したルール（前後の空白を除いたキー）を返す。
+func (s *NotificationService) AddSnooze(ctx context.Context, sn notification.Snooze) (notification.Snooze, error) {
+	if s.snoozeStore == nil {
+		return notification.Snooze{}, ErrSnoozeNotConfigured
+	}
+	sn.Key = strings.TrimSpace(sn.Key)
+	now := s.now()
+	switch {
+	case sn.Scope != notification.SnoozeTask && sn.Scope != notification.SnoozeProject:
+		return notification.Snooze{}, fmt.Errorf("%w: unknown scope %q (task or project)", ErrInvalidSnooze, sn.Scope)
+	case sn.Key == "":
+		return notification.Snooze{}, fmt.Errorf("%w: %s is required", ErrInvalidSnooze, sn.Scope)
+	case !sn.Active(now):
+		return notification.Snooze{}, fmt.Errorf("%w: until %s is in the past", ErrInvalidSnooze, sn.Until.Format("2006-01-02 15:04"))
+	}
+	if err := s.snoozeStore.Snooze(ctx, sn, now); err != nil {
+		return notification.Snooze{}, fmt.Errorf("failed to save snooze: %w", err)
+	}
+	return sn, nil
+}
+
+// RemoveSnooze はルールを削除する。該当するルールがなかった場合は ok が false になる。
+func (s *NotificationService) RemoveSnooze(ctx context.Context, scope notification.SnoozeScope, key string) (bool, error) {
+	if s.snoozeStore == nil {
+		return false, ErrSnoozeNotConfigured
+	}
+	ok, err := s.snoozeStore.Unsnooze(ctx, scope, strings.TrimSpace(key))
+	if err != nil {
+		return false, fmt.Errorf("failed to remove snooze: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+func TestNotificationService_SnoozeRules(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	chore := task.NewTask("1", "ゴミ出し", "家事", &due, task.StatusNotStarted)
+	report := task.NewTask("2", "資料作成", "Work", &due, task.StatusNotStarted)
+	muted := task.NewTask("3", "定例準備", "Work", &due, task.StatusNotStarted)
+	muted.Muted = true
+
+	store := newMemorySnoozeStore()
+	notifier := &mockNotifier{}
+	service := NewNotificationService(&mockTaskRepo{tasks: []*task.Task{chore, report, muted}}, singleChannel(notifier), 3,
+		WithClock(clock.Fixed(now)),
+		WithSnoozeStore(store),
+	)
+	ctx := context.Background()
+
+	// プロジェクト名は大文字小文字を区別せずに一致させ、期限なしは解除するまで止める
+	stored, err := service.AddSnooze(ctx, notification.Snooze{Scope: notification.SnoozeProject, Key: " 家事 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Key != "家事" {
+		t.Errorf("AddSnooze() key = %q, want 家事", stored.Key)
+	}
+	if err := service.NotifyUpcomingDeadlines(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contains(notifier.lastMessage, "ゴミ出し") || contains(notifier.lastMessage, "定例準備") || !contains(notifier.lastMessage, "資料作成") {
+		t.Errorf("expected snoozed project and muted task to be skipped, got: %s", notifier.lastMessage)
+	}
+
+	snoozes, err := service.ListSnoozes(ctx)
+	if err != nil || len(snoozes) != 1 || snoozes[0].Key != "家事" {
+		t.Fatalf("ListSnoozes() = %v, %v", snoozes, err)
+	}
+
+	if ok, err := service.RemoveSnooze(ctx, notification.SnoozeProject, "家事"); !ok || err != nil {
+		t.Fatalf("RemoveSnooze() = %v, %v", ok, err)
+	}
+	if ok, _ := service.RemoveSnooze(ctx, notification.SnoozeProject, "家事"); ok {
+		t.Error("expected second RemoveSnooze to report no rule")
+	}
+	if err := service.NotifyUpcomingDeadlines(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(notifier.lastMessage, "ゴミ出し") {
+		t.Errorf("expected task to be notified after unsnooze, got: %s", notifier.lastMessage)
+	}
+}
+
+func TestNotificationService_AddSnooze_Invalid(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	service := NewNotificationService(&mockTaskRepo{}, nil, 3, WithClock(clock.Fixed(now)), WithSnoozeStore(newMemorySnoozeStore()))
+
+	for _, sn := range []notification.Snooze{
+		{Scope: "label", Key: "x"},
+		{Scope: notification.SnoozeTask, Key: " "},
+		{Scope: notification.SnoozeTask, Key: "1", Until: now.Add(-time.Hour)},
+	} {
+		if _, err := service.AddSnooze(context.Background(), sn); !errors.Is(err, ErrInvalidSnooze) {
+			t.Errorf("AddSnooze(%+v): expected ErrInvalidSnooze, got %v", sn, err)
+		}
+	}
+
+	unconfigured := NewNotificationService(&mockTaskRepo{}, nil, 3)
+	if _, err := unconfigured.ListSnoozes(context.Background()); !errors.Is(err, ErrSnoozeNotConfigured) {
+		t.Errorf("expected ErrSnoozeNotConfigured, got %v", err)
+	}
+}
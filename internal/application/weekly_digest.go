@@ -0,0 +1,209 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+const defaultDigestDays = 7
+
+var weekdays = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+
+// NotifyWeeklyDigest は今後 digestDays 日以内に締切がある未完了タスク、締切超過のタスク、
+// 読書タスクの進捗を 1 つのメッセージにまとめて送信する。
+// 一覧を定期的に送るものなので、送信済み状態による重複抑制は行わない。
+// スヌーズ中・ミュートのタスクは他の通知と同じく含めない。
+func (s *NotificationService) NotifyWeeklyDigest(ctx context.Context) error {
+	upcoming, err := s.taskRepo.FetchTasksWithUpcomingDeadlines(ctx, s.digestDays)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	overdue, err := s.taskRepo.FetchOverdueTasks(ctx, s.maxDaysOverdue)
+	if err != nil {
+		return fmt.Errorf("failed to fetch overdue tasks: %w", err)
+	}
+	reading, err := s.taskRepo.FetchIncompleteStudyTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch study tasks: %w", err)
+	}
+
+	tasks, err := s.filterSnoozed(ctx, s.targets(slices.Concat(upcoming, overdue, reading)))
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	if _, err := s.dispatch(ctx, notification.KindDigest, tasks, s.buildWeeklyDigestMessage); err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", notification.KindDigest, err)
+	}
+	return nil
+}
+
+// ステータス別の件数、締切超過、日別（プロジェクト順）、プロジェクト別、読書の進捗の順に並べる。
+func (s *NotificationService) buildWeeklyDigestMessage(tasks []*task.Task) *notification.Message {
+	today := s.now()
+
+	var upcoming, overdue, reading []*task.Task
+	for _, t := range tasks {
+		switch {
+		case t.IsOverdue(today):
+			overdue = append(overdue, t)
+		case t.DueDate != nil && t.DaysUntilDeadline(today) <= s.digestDays:
+			upcoming = append(upcoming, t)
+		}
+		if t.TaskType == "Study" && t.TotalPages > 0 {
+			reading = append(reading, t)
+		}
+	}
+
+	msg := &notification.Message{
+		Title: fmt.Sprintf("🗓️ **今後%d日間のタスク (%s〜%s)**",
+			s.digestDays, formatDay(today), formatDay(today.AddDate(0, 0, s.digestDays))),
+	}
+	msg.Sections = append(msg.Sections, digestStatusSection(upcoming, overdue))
+
+	if len(overdue) > 0 {
+		slices.SortStableFunc(overdue, func(a, b *task.Task) int {
+			return b.DaysOverdue(today) - a.DaysOverdue(today)
+		})
+		sec := notification.Section{Title: "締切超過", Color: notification.ColorRed}
+		for _, t := range overdue {
+			sec.Items = append(sec.Items, taskItem(t, fmt.Sprintf("⚠️ %d日超過", t.DaysOverdue(today))))
+		}
+		msg.Sections = append(msg.Sections, sec)
+	}
+
+	msg.Sections = append(msg.Sections, daySections(upcoming, today)...)
+
+	if len(upcoming) > 0 {
+		msg.Sections = append(msg.Sections, digestProjectSection(upcoming, today))
+	}
+
+	if len(reading) > 0 {
+		slices.SortStableFunc(reading, func(a, b *task.Task) int {
+			return a.ReadingProgress() - b.ReadingProgress()
+		})
+		sec := notification.Section{Title: "読書の進捗", Color: notification.ColorBlue}
+		for _, t := range reading {
+			sec.Items = append(sec.Items, taskItem(t, fmt.Sprintf("📖 %d%% (%d/%dページ)", t.ReadingProgress(), t.ReadPages, t.TotalPages)))
+		}
+		msg.Sections = append(msg.Sections, sec)
+	}
+
+	return msg
+}
+
+// 期間内のタスクと締切超過のタスクをステータスごとに数える。
+func digestStatusSection(upcoming, overdue []*task.Task) notification.Section {
+	counts := make(map[task.Status]int)
+	var statuses []task.Status
+	for _, t := range slices.Concat(upcoming, overdue) {
+		if counts[t.Status] == 0 {
+			statuses = append(statuses, t.Status)
+		}
+		counts[t.Status]++
+	}
+	slices.Sort(statuses)
+
+	sec := notification.Section{
+		Title: "ステータス別",
+		Items: []notification.Item{
+			{Name: "期間内の締切", Detail: fmt.Sprintf("%d件", len(upcoming))},
+			{Name: "締切超過", Detail: fmt.Sprintf("%d件", len(overdue))},
+		},
+	}
+	for _, st := range statuses {
+		sec.Items = append(sec.Items, notification.Item{Name: string(st), Detail: fmt.Sprintf("%d件", counts[st])})
+	}
+	return sec
+}
+
+// プロジェクトごとの件数と最も近い締切。tasks は締切順に並んでいること。
+func digestProjectSection(tasks []*task.Task, today time.Time) notification.Section {
+	counts := make(map[string]int)
+	nearest := make(map[string]int)
+	var projects []string
+	for _, t := range tasks {
+		if counts[t.ProjectName] == 0 {
+			projects = append(projects, t.ProjectName)
+			nearest[t.ProjectName] = t.DaysUntilDeadline(today)
+		}
+		counts[t.ProjectName]++
+	}
+	slices.Sort(projects)
+
+	sec := notification.Section{Title: "プロジェクト別"}
+	for _, p := range projects {
+		name := p
+		if name == "" {
+			name = "(プロジェクトなし)"
+		}
+		sec.Items = append(sec.Items, notification.Item{
+			Name:   name,
+			Detail: fmt.Sprintf("%d件 / 直近の締切 %s", counts[p], formatDay(today.AddDate(0, 0, nearest[p]))),
+		})
+	}
+	return sec
+}
+
+func daysOf(tasks []*task.Task, today time.Time) []int {
+	days := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		days = append(days, t.DaysUntilDeadline(today))
+	}
+	return days
+}
+
+func digestColor(daysUntilDeadline int) notification.Color {
+	switch notification.SeverityOf(daysUntilDeadline) {
+	case notification.SeverityToday:
+		return notification.ColorRed
+	case notification.SeverityTomorrow:
+		return notification.ColorOrange
+	default:
+		return notification.ColorYellow
+	}
+}
+
+// daySections は締切日ごとのセクション（同じ日はプロジェクト順）を返す。tasks は並べ替えられる。
+func daySections(tasks []*task.Task, today time.Time) []notification.Section {
+	slices.SortStableFunc(tasks, func(a, b *task.Task) int {
+		return cmp.Or(
+			cmp.Compare(a.DaysUntilDeadline(today), b.DaysUntilDeadline(today)),
+			cmp.Compare(a.ProjectName, b.ProjectName),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	var sections []notification.Section
+	for _, day := range slices.Compact(daysOf(tasks, today)) {
+		sec := notification.Section{
+			Title: formatDay(today.AddDate(0, 0, day)),
+			Color: digestColor(day),
+		}
+		for _, t := range tasks {
+			if t.DaysUntilDeadline(today) == day {
+				var detail string
+				if at := dueClock(t); at != "" {
+					detail = at + " 締切"
+				}
+				sec.Items = append(sec.Items, taskItem(t, detail))
+			}
+		}
+		sections = append(sections, sec)
+	}
+	return sections
+}
+
+// 例: "3/2 (月)"
+func formatDay(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Format("1/2"), weekdays[t.Weekday()])
+}
@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+func TestNotificationService_NotifyWeeklyDigest(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	// 2026-03-02 は月曜日
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, jst)
+	day := func(offset int) *time.Time {
+		d := time.Date(2026, 3, 2+offset, 0, 0, 0, 0, jst)
+		return &d
+	}
+
+	book := task.NewTask("5", "Go本", "Study", day(20), task.StatusInProgress)
+	book.TaskType = "Study"
+	book.TotalPages = 300
+	book.ReadPages = 135
+
+	repo := &mockTaskRepo{tasks: []*task.Task{
+		task.NewTask("1", "Late Report", "Work", day(-2), task.StatusNotStarted),
+		task.NewTask("2", "Slides", "Work", day(0), task.StatusInProgress),
+		task.NewTask("3", "Groceries", "Personal", day(3), task.StatusNotStarted),
+		task.NewTask("4", "Review", "Work", day(3), task.StatusInProgress),
+		task.NewTask("6", "Next Month", "Work", day(30), task.StatusNotStarted),
+		task.NewTask("7", "Finished", "Work", day(1), task.StatusDone),
+		book,
+	}}
+	notifier := &mockNotifier{}
+	service := NewNotificationService(repo, singleChannel(notifier), 3, WithClock(clock.Fixed(now)))
+
+	if err := service.NotifyWeeklyDigest(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := notifier.lastMessage
+	for _, want := range []string{
+		"今後7日間のタスク (3/2 (月)〜3/9 (月))",
+		"- 期間内の締切: 3件",
+		"- 締切超過: 1件",
+		"- In Progress: 2件",
+		"- Not Started: 2件",
+		"- [Work] Late Report: ⚠️ 2日超過",
+		"**3/2 (月)**\n- [Work] Slides\n",
+		"**3/5 (木)**\n- [Personal] Groceries\n- [Work] Review\n",
+		"- Personal: 1件 / 直近の締切 3/5 (木)",
+		"- Work: 2件 / 直近の締切 3/2 (月)",
+		"- [Study] Go本: 📖 45% (135/300ページ)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected digest to contain %q, got:\n%s", want, msg)
+		}
+	}
+	for _, unwanted := range []string{"Next Month", "Finished"} {
+		if strings.Contains(msg, unwanted) {
+			t.Errorf("expected digest not to contain %q, got:\n%s", unwanted, msg)
+		}
+	}
+}
+
+func TestNotificationService_NotifyWeeklyDigest_Snoozed(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	chore := task.NewTask("1", "ゴミ出し", "家事", &due, task.StatusNotStarted)
+	report := task.NewTask("2", "資料作成", "Work", &due, task.StatusNotStarted)
+	muted := task.NewTask("3", "定例準備", "Work", &due, task.StatusNotStarted)
+	muted.Muted = true
+
+	store := newMemorySnoozeStore()
+	if err := store.Snooze(context.Background(), notification.Snooze{Scope: notification.SnoozeProject, Key: "家事"}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notifier := &mockNotifier{}
+	service := NewNotificationService(&mockTaskRepo{tasks: []*task.Task{chore, report, muted}}, singleChannel(notifier), 3,
+		WithClock(clock.Fixed(now)),
+		WithSnoozeStore(store),
+	)
+
+	if err := service.NotifyWeeklyDigest(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg := notifier.lastMessage
+	if !strings.Contains(msg, "資料作成") || !strings.Contains(msg, "- 期間内の締切: 1件") {
+		t.Errorf("expected digest to contain only the active task, got:\n%s", msg)
+	}
+	for _, unwanted := range []string{"ゴミ出し", "定例準備"} {
+		if strings.Contains(msg, unwanted) {
+			t.Errorf("expected digest not to contain %q, got:\n%s", unwanted, msg)
+		}
+	}
+}
+
+func TestNotificationService_NotifyWeeklyDigest_NoTasks(t *testing.T) {
+	notifier := &mockNotifier{}
+	service := NewNotificationService(&mockTaskRepo{}, singleChannel(notifier), 3, WithDigestDays(14))
+
+	if err := service.NotifyWeeklyDigest(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notifier.lastMessage != "" {
+		t.Errorf("expected no message, got: %s", notifier.lastMessage)
+	}
+}
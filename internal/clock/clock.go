@@ -0,0 +1,46 @@
+package clock
+
+import "time"
+
+// Clock は現在時刻を返す。テストや過去日時での再現のために差し替えられる。
+type Clock interface {
+	Now() time.Time
+}
+
+// System は実際の現在時刻を loc のタイムゾーンで返す。loc が nil の場合は time.Local。
+func System(loc *time.Location) Clock {
+	if loc == nil {
+		loc = time.Local
+	}
+	return systemClock{loc: loc}
+}
+
+type systemClock struct {
+	loc *time.Location
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+// Fixed は常に t を返す。
+func Fixed(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// Parse は "2006-01-02" または RFC3339 形式の時刻を loc で解釈する。
+// 日付のみの場合はその日の 00:00 とする。
+func Parse(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
+	}
+	return time.ParseInLocation("2006-01-02", s, loc)
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,25 +13,236 @@ type Config struct {
 	Server       ServerConfig       `yaml:"server"`
 	Notion       NotionConfig       `yaml:"notion"`
 	Discord      DiscordConfig      `yaml:"discord"`
+	Channels     []ChannelConfig    `yaml:"channels"`
 	Notification NotificationConfig `yaml:"notification"`
+	Jobs         []JobConfig        `yaml:"jobs"`
+	Retry        RetryConfig        `yaml:"retry"`
+	Actions      ActionsConfig      `yaml:"actions"`
 }
 
 type ServerConfig struct {
 	Port int `yaml:"port"`
+	// 設定を変更する API（POST /snoozes など）の Bearer トークン（16 文字以上）。省略時はそれらの API を無効にする。
+	// 設定すると /run, /jobs, /tasks/upcoming, GET /snoozes にも必要になる。
+	// 操作リンクや Discord ボットで serve を外部に公開する場合は必須
+	AdminToken string `yaml:"admin_token"`
 }
 
+const minAdminTokenLength = 16
+
 type NotionConfig struct {
 	APIToken   string `yaml:"api_token"`
 	DatabaseID string `yaml:"database_id"`
+	PageSize   int    `yaml:"page_size"` // 1リクエストあたりの取得件数 (1〜100, 省略時 100)
+	MaxPages   int    `yaml:"max_pages"` // ページネーションで辿る最大リクエスト数 (省略時 50)
+	// task.Task の各フィールドに対応する Notion プロパティ。省略したものはデフォルト名・型を使う。
+	Properties NotionPropertiesConfig `yaml:"properties"`
+	// ステータス名ごとのグループ。status 型なら Notion のステータスグループより優先される
+	StatusGroups StatusGroupsConfig `yaml:"status_groups"`
+	// 優先度プロパティの値。省略時は High/Medium/Low と 高/中/低
+	Priorities PrioritiesConfig `yaml:"priorities"`
+}
+
+type PrioritiesConfig struct {
+	High   []string `yaml:"high"`
+	Medium []string `yaml:"medium"`
+	Low    []string `yaml:"low"`
+}
+
+// グループごとのステータス名。ここにも Notion のステータスグループにもないステータスは未完了として扱う。
+type StatusGroupsConfig struct {
+	ToDo       []string `yaml:"to_do"`
+	InProgress []string `yaml:"in_progress"`
+	Complete   []string `yaml:"complete"` // 通知対象外
+}
+
+type NotionPropertiesConfig struct {
+	TaskName   NotionPropertyConfig `yaml:"task_name"`
+	Due        NotionPropertyConfig `yaml:"due"`
+	Status     NotionPropertyConfig `yaml:"status"`
+	Project    NotionPropertyConfig `yaml:"project"`
+	TaskType   NotionPropertyConfig `yaml:"task_type"`
+	StartDate  NotionPropertyConfig `yaml:"start_date"`
+	TotalPages NotionPropertyConfig `yaml:"total_pages"`
+	ReadPages  NotionPropertyConfig `yaml:"read_pages"`
+	// タスクごとの読書ペース（ページ/日）。reading_pace より優先される
+	PagesPerDay NotionPropertyConfig `yaml:"pages_per_day"`
+	Priority    NotionPropertyConfig `yaml:"priority"`
+	// チェックすると通知しないチェックボックス（省略時「通知しない」）
+	Mute NotionPropertyConfig `yaml:"mute"`
 }
 
+type NotionPropertyConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // title, rich_text, date, status, select, relation, number, checkbox
+}
+
+// channels を指定しない場合、webhook_url がすべての通知を受け取る "default" チャネルになる。
 type DiscordConfig struct {
+	WebhookURL string           `yaml:"webhook_url"`
+	Bot        DiscordBotConfig `yaml:"bot"`
+}
+
+// スラッシュコマンドに応答するボットの設定。public_key を指定すると serve で
+// POST /discord/interactions を受け付ける（Developer Portal の Interactions Endpoint URL に登録する）。
+type DiscordBotConfig struct {
+	PublicKey     string `yaml:"public_key"`
+	ApplicationID string `yaml:"application_id"` // register-commands で使う
+	Token         string `yaml:"token"`          // register-commands で使うボットのトークン
+	GuildID       string `yaml:"guild_id"`       // 指定するとそのサーバーだけにコマンドを登録する
+}
+
+// Enabled はボットのインタラクションを受け付けるかどうかを返す。
+func (c DiscordBotConfig) Enabled() bool {
+	return c.PublicKey != ""
+}
+
+// 通知に付ける操作リンク（完了・ページ数を進める・延期）の設定。base_url を指定すると有効になり、
+// serve が GET/POST /actions を受け付ける。
+type ActionsConfig struct {
+	BaseURL      string        `yaml:"base_url"`      // 通知を受け取る端末から開ける serve の URL（例: https://notifier.example.com）
+	Secret       string        `yaml:"secret"`        // リンクの署名鍵（16 文字以上）
+	DoneStatus   string        `yaml:"done_status"`   // 「完了」で設定するステータス。省略時 Done
+	PagesStep    int           `yaml:"pages_step"`    // 「+N ページ」のページ数。省略時 20
+	PostponeDays int           `yaml:"postpone_days"` // 「N 日延期」の日数。省略時 1
+	TTL          time.Duration `yaml:"ttl"`           // リンクの有効期間。省略時 168h
+}
+
+// Enabled は操作リンクを付けるかどうかを返す。
+func (c ActionsConfig) Enabled() bool {
+	return c.BaseURL != ""
+}
+
+const minActionSecretLength = 16
+
+func (c ActionsConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if len(c.Secret) < minActionSecretLength {
+		return fmt.Errorf("actions.secret must be at least %d characters", minActionSecretLength)
+	}
+	if c.PagesStep < 0 || c.PostponeDays < 0 || c.TTL < 0 {
+		return fmt.Errorf("actions settings must not be negative")
+	}
+	return nil
+}
+
+// 名前付きの通知チャネル。routes のいずれかに一致した通知だけを受け取る（routes が空ならすべて）。
+type ChannelConfig struct {
+	Name       string `yaml:"name"`
+	Type       string `yaml:"type"` // discord（省略時）, slack, teams, webhook, email
 	WebhookURL string `yaml:"webhook_url"`
+	// type: webhook の本文テンプレート（Go の text/template）。省略時は {"text": "..."}
+	Template string `yaml:"template"`
+	// type: email の送信設定
+	Email  EmailConfig   `yaml:"email"`
+	Routes []RouteConfig `yaml:"routes"`
+}
+
+// SMTP でメールを送る通知チャネルの設定。
+type EmailConfig struct {
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port"` // 省略時 587
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	StartTLS bool          `yaml:"starttls"` // true の場合、サーバーが STARTTLS に対応していなければ送信しない
+	From     string        `yaml:"from"`
+	To       []string      `yaml:"to"`
+	Timeout  time.Duration `yaml:"timeout"` // 省略時 30s
+}
+
+// 振り分け条件。指定した項目はすべて満たす必要がある。
+type RouteConfig struct {
+	Kinds      []string `yaml:"kinds"`      // deadline, reading, overdue, digest
+	Projects   []string `yaml:"projects"`   // プロジェクト名
+	TaskTypes  []string `yaml:"task_types"` // タスク種別
+	Severities []string `yaml:"severities"` // today, tomorrow, later
 }
 
 type NotificationConfig struct {
 	DaysBefore    int    `yaml:"days_before"`
 	CheckSchedule string `yaml:"check_schedule"` // cron形式: "0 12 * * *" = 毎日12時
+	// 優先度 (high, medium, low, none) ごとの days_before。指定しない優先度は days_before を使う
+	DaysBeforeByPriority map[string]int `yaml:"days_before_by_priority"`
+	// 時刻付きの締切のタスクを締切の何時間前からも通知するか（例: 2）。0 の場合は日単位のみ
+	HoursBefore int `yaml:"hours_before"`
+	// 「今日」の判定と check_schedule に使うタイムゾーン（IANA 名）。省略時は Asia/Tokyo
+	Timezone string `yaml:"timezone"`
+	// 送信済み状態を保存するファイル。指定すると新規または緊急度が上がった通知だけを送る
+	StateFile string `yaml:"state_file"`
+	// /snooze で止めたタスクを保存するファイル。省略時は /snooze を使えない
+	SnoozeFile string `yaml:"snooze_file"`
+	// 同じ通知を再送するまでの間隔。0 の場合は緊急度が上がるまで再送しない
+	ResendCooldown time.Duration    `yaml:"resend_cooldown"`
+	Overdue        OverdueConfig    `yaml:"overdue"`
+	ReadingPace    PaceConfig       `yaml:"reading_pace"`
+	Digest         DigestConfig     `yaml:"digest"`
+	Escalation     EscalationConfig `yaml:"escalation"`
+}
+
+// 締切通知を送り直す段階。指定すると、締切の days 日前の段をまたいだときだけ通知する。state_file が必要。
+type EscalationConfig struct {
+	Days           []int  `yaml:"days"`              // 例: [7, 3, 1, 0]
+	HourlyOnDueDay bool   `yaml:"hourly_on_due_day"` // 締切日は時刻付きのタスクを 1 時間ごとに通知する（ジョブも 1 時間ごとに実行すること）
+	DueDayMention  string `yaml:"due_day_mention"`   // 締切当日のタスクを含む通知に付けるメンション。例: "@here"
+}
+
+// 週次ダイジェスト（jobs の kinds に "digest" を指定したジョブで送信）の設定。
+type DigestConfig struct {
+	Days int `yaml:"days"` // 何日先までの締切を含めるか (省略時 7)
+}
+
+// 読書タスクの目標ペース。
+type PaceConfig struct {
+	Mode        string `yaml:"mode"`          // fixed: 締切から 1 日 pages_per_day で逆算 / linear: 開始日〜締切で均等
+	PagesPerDay int    `yaml:"pages_per_day"` // fixed のときの 1 日あたりのページ数 (省略時 30)
+}
+
+// 締切を過ぎた未完了タスクの通知設定。
+type OverdueConfig struct {
+	Enabled bool `yaml:"enabled"`
+	MaxDays int  `yaml:"max_days"` // 何日前に締切を過ぎたタスクまで通知するか。0 は無制限
+}
+
+// スケジュール実行するジョブ。jobs を指定しない場合は check_schedule で
+// すべての通知を送る "notify" ジョブ 1 つになる。
+type JobConfig struct {
+	Name     string        `yaml:"name"`
+	Schedule string        `yaml:"schedule"` // cron形式 (timezone で解釈)
+	Timeout  time.Duration `yaml:"timeout"`  // 省略時 60s
+	Enabled  *bool         `yaml:"enabled"`  // 省略時 true。false でも POST /run?job= や run-once -job では実行できる
+	Kinds    []string      `yaml:"kinds"`    // 送信する通知 (deadline, reading, overdue, digest)。省略時は digest 以外すべて
+	// 前回の実行が終わっていないときの扱い。skip: 実行しない（デフォルト）/ queue: 終わるのを待って実行する
+	Overlap string `yaml:"overlap"`
+}
+
+// IsEnabled は enabled が省略されていれば true を返す。
+func (c JobConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// Notion / Discord へのリクエストが 429 や 5xx で失敗したときの再試行設定。
+// 省略した項目はデフォルト（3 回, 1s, 30s）を使う。
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts"`    // 初回を含む最大試行回数。1 で再試行しない
+	InitialBackoff time.Duration `yaml:"initial_backoff"` // 例: "1s"
+	MaxBackoff     time.Duration `yaml:"max_backoff"`     // 例: "30s"
+}
+
+const defaultTimezone = "Asia/Tokyo"
+
+// Location は notification.timezone のタイムゾーンを返す。validate 済みであることを前提とする。
+func (c NotificationConfig) Location() *time.Location {
+	name := c.Timezone
+	if name == "" {
+		name = defaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
 
 func Load(path string) (*Config, error) {
@@ -57,11 +270,215 @@ func (c *Config) validate() error {
 	if c.Notion.APIToken == "" {
 		return fmt.Errorf("notion.api_token is required")
 	}
+	if c.Server.AdminToken != "" && len(c.Server.AdminToken) < minAdminTokenLength {
+		return fmt.Errorf("server.admin_token must be at least %d characters", minAdminTokenLength)
+	}
 	if c.Notion.DatabaseID == "" {
 		return fmt.Errorf("notion.database_id is required")
 	}
-	if c.Discord.WebhookURL == "" {
-		return fmt.Errorf("discord.webhook_url is required")
+	if c.Notion.PageSize < 0 || c.Notion.PageSize > 100 {
+		return fmt.Errorf("notion.page_size must be between 0 and 100 (0 = default)")
+	}
+	if c.Notion.MaxPages < 0 {
+		return fmt.Errorf("notion.max_pages must not be negative")
+	}
+	if err := c.Notion.StatusGroups.validate(); err != nil {
+		return err
+	}
+	if err := c.Notion.Priorities.validate(); err != nil {
+		return err
+	}
+	if c.Notification.Timezone != "" {
+		if _, err := time.LoadLocation(c.Notification.Timezone); err != nil {
+			return fmt.Errorf("notification.timezone: %w", err)
+		}
+	}
+	for p, days := range c.Notification.DaysBeforeByPriority {
+		if err := validateValues([]string{p}, validPriorities); err != nil {
+			return fmt.Errorf("notification.days_before_by_priority: %w", err)
+		}
+		if days < 0 {
+			return fmt.Errorf("notification.days_before_by_priority.%s must not be negative", p)
+		}
+	}
+	if c.Notification.HoursBefore < 0 {
+		return fmt.Errorf("notification.hours_before must not be negative")
+	}
+	if err := c.Notification.Escalation.validate(c.Notification.StateFile); err != nil {
+		return err
+	}
+	if c.Notification.ResendCooldown < 0 {
+		return fmt.Errorf("notification.resend_cooldown must not be negative")
+	}
+	if c.Notification.Overdue.MaxDays < 0 {
+		return fmt.Errorf("notification.overdue.max_days must not be negative")
+	}
+	if err := validateValues([]string{c.Notification.ReadingPace.Mode}, []string{"", "fixed", "linear"}); err != nil {
+		return fmt.Errorf("notification.reading_pace.mode: %w", err)
+	}
+	if c.Notification.Digest.Days < 0 {
+		return fmt.Errorf("notification.digest.days must not be negative")
+	}
+	if c.Notification.ReadingPace.PagesPerDay < 0 {
+		return fmt.Errorf("notification.reading_pace.pages_per_day must not be negative")
+	}
+	if c.Retry.MaxAttempts < 0 || c.Retry.InitialBackoff < 0 || c.Retry.MaxBackoff < 0 {
+		return fmt.Errorf("retry settings must not be negative")
+	}
+	if len(c.Channels) == 0 && c.Discord.WebhookURL == "" {
+		return fmt.Errorf("discord.webhook_url or channels is required")
+	}
+	if err := validateChannels(c.Channels); err != nil {
+		return err
+	}
+	if err := validateJobs(c.Jobs); err != nil {
+		return err
+	}
+	if err := c.Actions.validate(); err != nil {
+		return err
+	}
+	if (c.Actions.Enabled() || c.Discord.Bot.Enabled()) && c.Server.AdminToken == "" {
+		return fmt.Errorf("server.admin_token is required when actions or discord.bot is enabled")
+	}
+	return nil
+}
+
+var (
+	validKinds      = []string{"deadline", "reading", "overdue", "digest"}
+	validSeverities = []string{"today", "tomorrow", "later"}
+	validChannels   = []string{"discord", "slack", "teams", "webhook", "email"}
+	validPriorities = []string{"high", "medium", "low", "none"}
+)
+
+func (c StatusGroupsConfig) validate() error {
+	return validateDisjoint("notion.status_groups", []namedValues{
+		{"to_do", c.ToDo}, {"in_progress", c.InProgress}, {"complete", c.Complete},
+	})
+}
+
+func (c PrioritiesConfig) validate() error {
+	return validateDisjoint("notion.priorities", []namedValues{
+		{"high", c.High}, {"medium", c.Medium}, {"low", c.Low},
+	})
+}
+
+func (c EscalationConfig) validate(stateFile string) error {
+	for _, d := range c.Days {
+		if d < 0 {
+			return fmt.Errorf("notification.escalation.days must not be negative")
+		}
+	}
+	if (len(c.Days) > 0 || c.HourlyOnDueDay) && stateFile == "" {
+		return fmt.Errorf("notification.escalation requires notification.state_file")
+	}
+	return nil
+}
+
+type namedValues struct {
+	name   string
+	values []string
+}
+
+// 同じ値が複数のグループに含まれていないことを検証する。
+func validateDisjoint(field string, groups []namedValues) error {
+	seen := make(map[string]string)
+	for _, g := range groups {
+		for _, v := range g.values {
+			if prev, ok := seen[v]; ok {
+				return fmt.Errorf("%s: %q is listed in both %s and %s", field, v, prev, g.name)
+			}
+			seen[v] = g.name
+		}
+	}
+	return nil
+}
+
+func validateChannels(channels []ChannelConfig) error {
+	names := make(map[string]bool)
+	for i, ch := range channels {
+		if ch.Name == "" {
+			return fmt.Errorf("channels[%d].name is required", i)
+		}
+		if names[ch.Name] {
+			return fmt.Errorf("channels[%d].name %q is duplicated", i, ch.Name)
+		}
+		names[ch.Name] = true
+
+		if ch.Type != "" {
+			if err := validateValues([]string{ch.Type}, validChannels); err != nil {
+				return fmt.Errorf("channels[%d].type: %w", i, err)
+			}
+		}
+		if ch.Type == "email" {
+			if err := ch.Email.validate(); err != nil {
+				return fmt.Errorf("channels[%d].email.%w", i, err)
+			}
+		} else if ch.WebhookURL == "" {
+			return fmt.Errorf("channels[%d].webhook_url is required", i)
+		}
+		if ch.Template != "" && ch.Type != "webhook" {
+			return fmt.Errorf("channels[%d].template is only supported for type webhook", i)
+		}
+		for j, r := range ch.Routes {
+			if err := validateValues(r.Kinds, validKinds); err != nil {
+				return fmt.Errorf("channels[%d].routes[%d].kinds: %w", i, j, err)
+			}
+			if err := validateValues(r.Severities, validSeverities); err != nil {
+				return fmt.Errorf("channels[%d].routes[%d].severities: %w", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c EmailConfig) validate() error {
+	switch {
+	case c.Host == "":
+		return fmt.Errorf("host is required")
+	case c.From == "":
+		return fmt.Errorf("from is required")
+	case len(c.To) == 0:
+		return fmt.Errorf("to is required")
+	case c.Port < 0 || c.Port > 65535:
+		return fmt.Errorf("port %d is out of range", c.Port)
+	case c.Timeout < 0:
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
+
+func validateJobs(jobs []JobConfig) error {
+	names := make(map[string]bool)
+	for i, j := range jobs {
+		if j.Name == "" {
+			return fmt.Errorf("jobs[%d].name is required", i)
+		}
+		if names[j.Name] {
+			return fmt.Errorf("jobs[%d].name %q is duplicated", i, j.Name)
+		}
+		names[j.Name] = true
+
+		if j.Schedule == "" {
+			return fmt.Errorf("jobs[%d].schedule is required", i)
+		}
+		if j.Timeout < 0 {
+			return fmt.Errorf("jobs[%d].timeout must not be negative", i)
+		}
+		if err := validateValues(j.Kinds, validKinds); err != nil {
+			return fmt.Errorf("jobs[%d].kinds: %w", i, err)
+		}
+		if err := validateValues([]string{j.Overlap}, []string{"", "skip", "queue"}); err != nil {
+			return fmt.Errorf("jobs[%d].overlap: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateValues(values, allowed []string) error {
+	for _, v := range values {
+		if !slices.Contains(allowed, v) {
+			return fmt.Errorf("unsupported value %q (allowed: %v)", v, allowed)
+		}
 	}
 	return nil
 }
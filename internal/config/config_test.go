@@ -0,0 +1,426 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() Config {
+	return Config{
+		Notion:  NotionConfig{APIToken: "secret", DatabaseID: "db"},
+		Discord: DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/1/x"},
+	}
+}
+
+type validateTest struct {
+	name    string
+	modify  func(c *Config)
+	wantErr string
+}
+
+func runValidateTests(t *testing.T, tests []validateTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{name: "minimal", modify: func(c *Config) {}},
+		{
+			name:   "default page size",
+			modify: func(c *Config) { c.Notion.PageSize = 0 },
+		},
+		{
+			name:    "page size out of range",
+			modify:  func(c *Config) { c.Notion.PageSize = 101 },
+			wantErr: "notion.page_size must be between 0 and 100 (0 = default)",
+		},
+
+		// エスカレーション
+		{
+			name: "escalation with state file",
+			modify: func(c *Config) {
+				c.Notification.StateFile = "/data/state.json"
+				c.Notification.Escalation = EscalationConfig{Days: []int{7, 3, 1, 0}, HourlyOnDueDay: true}
+			},
+		},
+		{
+			name: "negative escalation day",
+			modify: func(c *Config) {
+				c.Notification.StateFile = "/data/state.json"
+				c.Notification.Escalation.Days = []int{3, -1}
+			},
+			wantErr: "notification.escalation.days must not be negative",
+		},
+		{
+			name:    "escalation without state file",
+			modify:  func(c *Config) { c.Notification.Escalation.Days = []int{3, 1} },
+			wantErr: "notification.escalation requires notification.state_file",
+		},
+		{
+			name:    "hourly escalation without state file",
+			modify:  func(c *Config) { c.Notification.Escalation.HourlyOnDueDay = true },
+			wantErr: "notification.escalation requires notification.state_file",
+		},
+	})
+}
+
+func TestConfig_ValidateChannels(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name:    "missing webhook and channels",
+			modify:  func(c *Config) { c.Discord.WebhookURL = "" },
+			wantErr: "discord.webhook_url or channels is required",
+		},
+		{
+			name: "channels without discord webhook",
+			modify: func(c *Config) {
+				c.Discord.WebhookURL = ""
+				c.Channels = []ChannelConfig{
+					{Name: "work", WebhookURL: "https://discord.example.com/1", Routes: []RouteConfig{{Kinds: []string{"deadline"}, Severities: []string{"today"}}}},
+					{Name: "books", WebhookURL: "https://discord.example.com/2", Routes: []RouteConfig{{Kinds: []string{"reading"}}}},
+				}
+			},
+		},
+		{
+			name:    "channel without name",
+			modify:  func(c *Config) { c.Channels = []ChannelConfig{{WebhookURL: "https://example.com"}} },
+			wantErr: "channels[0].name is required",
+		},
+		{
+			name: "duplicated channel name",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{
+					{Name: "work", WebhookURL: "https://example.com/1"},
+					{Name: "work", WebhookURL: "https://example.com/2"},
+				}
+			},
+			wantErr: `channels[1].name "work" is duplicated`,
+		},
+		{
+			name:    "channel without webhook url",
+			modify:  func(c *Config) { c.Channels = []ChannelConfig{{Name: "work"}} },
+			wantErr: "channels[0].webhook_url is required",
+		},
+		{
+			name: "unknown route kind",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "work", WebhookURL: "https://example.com", Routes: []RouteConfig{{Kinds: []string{"birthday"}}}}}
+			},
+			wantErr: "channels[0].routes[0].kinds: unsupported value",
+		},
+		{
+			name: "unknown route severity",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "urgent", WebhookURL: "https://example.com", Routes: []RouteConfig{{Severities: []string{"now"}}}}}
+			},
+			wantErr: "channels[0].routes[0].severities: unsupported value",
+		},
+	})
+}
+
+func TestConfig_ValidateTimezone(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name:   "timezone",
+			modify: func(c *Config) { c.Notification.Timezone = "Asia/Tokyo" },
+		},
+		{
+			name:    "unknown timezone",
+			modify:  func(c *Config) { c.Notification.Timezone = "Mars/Olympus" },
+			wantErr: "notification.timezone",
+		},
+	})
+}
+
+func TestConfig_ValidateJobs(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name: "jobs",
+			modify: func(c *Config) {
+				c.Jobs = []JobConfig{
+					{Name: "deadlines", Schedule: "0 9 * * *", Kinds: []string{"deadline", "overdue"}},
+					{Name: "reading", Schedule: "0 21 * * *", Timeout: time.Minute, Kinds: []string{"reading"}},
+				}
+			},
+		},
+		{
+			name:    "job without name",
+			modify:  func(c *Config) { c.Jobs = []JobConfig{{Schedule: "0 9 * * *"}} },
+			wantErr: "jobs[0].name is required",
+		},
+		{
+			name: "duplicated job name",
+			modify: func(c *Config) {
+				c.Jobs = []JobConfig{{Name: "notify", Schedule: "0 9 * * *"}, {Name: "notify", Schedule: "0 21 * * *"}}
+			},
+			wantErr: `jobs[1].name "notify" is duplicated`,
+		},
+		{
+			name:    "job without schedule",
+			modify:  func(c *Config) { c.Jobs = []JobConfig{{Name: "notify"}} },
+			wantErr: "jobs[0].schedule is required",
+		},
+		{
+			name:    "negative job timeout",
+			modify:  func(c *Config) { c.Jobs = []JobConfig{{Name: "notify", Schedule: "0 9 * * *", Timeout: -time.Second}} },
+			wantErr: "jobs[0].timeout must not be negative",
+		},
+		{
+			name: "unknown job kind",
+			modify: func(c *Config) {
+				c.Jobs = []JobConfig{{Name: "weekly", Schedule: "0 9 * * 1", Kinds: []string{"weekly"}}}
+			},
+			wantErr: "jobs[0].kinds: unsupported value",
+		},
+	})
+}
+
+func TestConfig_ValidateJobOverlap(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name: "overlap policies",
+			modify: func(c *Config) {
+				c.Jobs = []JobConfig{
+					{Name: "deadlines", Schedule: "0 9 * * *", Overlap: "skip"},
+					{Name: "reading", Schedule: "0 21 * * *", Overlap: "queue"},
+				}
+			},
+		},
+		{
+			name:    "unknown overlap policy",
+			modify:  func(c *Config) { c.Jobs = []JobConfig{{Name: "notify", Schedule: "0 9 * * *", Overlap: "parallel"}} },
+			wantErr: "jobs[0].overlap: unsupported value",
+		},
+	})
+}
+
+func TestConfig_ValidatePriorities(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name:   "days before by priority",
+			modify: func(c *Config) { c.Notification.DaysBeforeByPriority = map[string]int{"high": 7, "none": 0} },
+		},
+		{
+			name:    "unknown priority",
+			modify:  func(c *Config) { c.Notification.DaysBeforeByPriority = map[string]int{"urgent": 7} },
+			wantErr: "notification.days_before_by_priority: unsupported value",
+		},
+		{
+			name:    "negative days before by priority",
+			modify:  func(c *Config) { c.Notification.DaysBeforeByPriority = map[string]int{"low": -1} },
+			wantErr: "notification.days_before_by_priority.low must not be negative",
+		},
+		{
+			name: "priority values",
+			modify: func(c *Config) {
+				c.Notion.Priorities = PrioritiesConfig{High: []string{"P1"}, Medium: []string{"P2"}, Low: []string{"P3"}}
+			},
+		},
+		{
+			name: "priority value in two levels",
+			modify: func(c *Config) {
+				c.Notion.Priorities = PrioritiesConfig{High: []string{"P1"}, Low: []string{"P1"}}
+			},
+			wantErr: `notion.priorities: "P1" is listed in both high and low`,
+		},
+	})
+}
+
+func TestConfig_ValidateHoursBefore(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name:   "hours before",
+			modify: func(c *Config) { c.Notification.HoursBefore = 3 },
+		},
+		{
+			name:    "negative hours before",
+			modify:  func(c *Config) { c.Notification.HoursBefore = -2 },
+			wantErr: "notification.hours_before must not be negative",
+		},
+	})
+}
+
+func TestConfig_ValidateChannelTypes(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name: "channels of each type",
+			modify: func(c *Config) {
+				c.Discord.WebhookURL = ""
+				c.Channels = []ChannelConfig{
+					{Name: "discord", WebhookURL: "https://discord.example.com"},
+					{Name: "slack", Type: "slack", WebhookURL: "https://hooks.slack.com/x"},
+					{Name: "teams", Type: "teams", WebhookURL: "https://teams.example.com"},
+					{Name: "hook", Type: "webhook", WebhookURL: "https://example.com", Template: `{"text": {{json .Text}}}`},
+				}
+			},
+		},
+		{
+			name: "unknown channel type",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "line", Type: "line", WebhookURL: "https://example.com"}}
+			},
+			wantErr: "channels[0].type: unsupported value",
+		},
+		{
+			name:    "slack channel without webhook url",
+			modify:  func(c *Config) { c.Channels = []ChannelConfig{{Name: "slack", Type: "slack"}} },
+			wantErr: "channels[0].webhook_url is required",
+		},
+		{
+			name: "template on non-webhook channel",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "slack", Type: "slack", WebhookURL: "https://hooks.slack.com/x", Template: "{{.Text}}"}}
+			},
+			wantErr: "channels[0].template is only supported for type webhook",
+		},
+	})
+}
+
+func TestConfig_ValidateEmail(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name: "email channel",
+			modify: func(c *Config) {
+				c.Discord.WebhookURL = ""
+				c.Channels = []ChannelConfig{{Name: "mail", Type: "email", Email: EmailConfig{Host: "smtp.example.com", Port: 587, From: "a@example.com", To: []string{"b@example.com"}}}}
+			},
+		},
+		{
+			name: "email without host",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "mail", Type: "email", Email: EmailConfig{From: "a@example.com", To: []string{"b@example.com"}}}}
+			},
+			wantErr: "channels[0].email.host is required",
+		},
+		{
+			name: "email without from",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "mail", Type: "email", Email: EmailConfig{Host: "smtp", To: []string{"b@example.com"}}}}
+			},
+			wantErr: "channels[0].email.from is required",
+		},
+		{
+			name: "email without recipients",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "mail", Type: "email", Email: EmailConfig{Host: "smtp", From: "a@example.com"}}}
+			},
+			wantErr: "channels[0].email.to is required",
+		},
+		{
+			name: "email port out of range",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "mail", Type: "email", Email: EmailConfig{Host: "smtp", Port: 70000, From: "a@example.com", To: []string{"b@example.com"}}}}
+			},
+			wantErr: "channels[0].email.port 70000 is out of range",
+		},
+		{
+			name: "email negative timeout",
+			modify: func(c *Config) {
+				c.Channels = []ChannelConfig{{Name: "mail", Type: "email", Email: EmailConfig{Host: "smtp", From: "a@example.com", To: []string{"b@example.com"}, Timeout: -time.Second}}}
+			},
+			wantErr: "channels[0].email.timeout must not be negative",
+		},
+	})
+}
+
+func TestConfig_ValidateActions(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name: "actions",
+			modify: func(c *Config) {
+				c.Server.AdminToken = "0123456789abcdef"
+				c.Actions = ActionsConfig{BaseURL: "https://notifier.example.com", Secret: "0123456789abcdef"}
+			},
+		},
+		{
+			name: "actions without admin token",
+			modify: func(c *Config) {
+				c.Actions = ActionsConfig{BaseURL: "https://notifier.example.com", Secret: "0123456789abcdef"}
+			},
+			wantErr: "server.admin_token is required when actions or discord.bot is enabled",
+		},
+		{
+			name:    "discord bot without admin token",
+			modify:  func(c *Config) { c.Discord.Bot.PublicKey = "abcd" },
+			wantErr: "server.admin_token is required when actions or discord.bot is enabled",
+		},
+		{
+			name:   "actions disabled without secret",
+			modify: func(c *Config) { c.Actions = ActionsConfig{PagesStep: 10} },
+		},
+		{
+			name:    "short action secret",
+			modify:  func(c *Config) { c.Actions = ActionsConfig{BaseURL: "https://notifier.example.com", Secret: "short"} },
+			wantErr: "actions.secret must be at least 16 characters",
+		},
+		{
+			name: "negative action settings",
+			modify: func(c *Config) {
+				c.Server.AdminToken = "0123456789abcdef"
+				c.Actions = ActionsConfig{BaseURL: "https://notifier.example.com", Secret: "0123456789abcdef", PostponeDays: -1}
+			},
+			wantErr: "actions settings must not be negative",
+		},
+	})
+}
+
+func TestConfig_ValidateAdminToken(t *testing.T) {
+	runValidateTests(t, []validateTest{
+		{
+			name:   "admin token",
+			modify: func(c *Config) { c.Server.AdminToken = "0123456789abcdef" },
+		},
+		{
+			name:    "short admin token",
+			modify:  func(c *Config) { c.Server.AdminToken = "short" },
+			wantErr: "server.admin_token must be at least 16 characters",
+		},
+	})
+}
+
+func TestLoad_ExpandsEnv(t *testing.T) {
+	t.Setenv("NOTION_API_TOKEN", "from-env")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+notion:
+  api_token: ${NOTION_API_TOKEN}
+  database_id: db
+discord:
+  webhook_url: https://discord.com/api/webhooks/1/x
+notification:
+  snooze_file: /data/snooze.json
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Notion.APIToken != "from-env" {
+		t.Errorf("APIToken = %q, want from-env", cfg.Notion.APIToken)
+	}
+	if cfg.Notification.SnoozeFile != "/data/snooze.json" {
+		t.Errorf("SnoozeFile = %q", cfg.Notification.SnoozeFile)
+	}
+}
@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+// Kind は通知の種類。
+type Kind string
+
+const (
+	KindDeadline Kind = "deadline"
+	KindReading  Kind = "reading"
+	KindOverdue  Kind = "overdue"
+	KindDigest   Kind = "digest"
+)
+
+// Severity は締切通知の緊急度。締切までの日数から決まる。
+type Severity string
+
+const (
+	SeverityToday    Severity = "today"
+	SeverityTomorrow Severity = "tomorrow"
+	SeverityLater    Severity = "later"
+)
+
+// SeverityOf は締切までの日数から緊急度を返す。
+func SeverityOf(daysUntilDeadline int) Severity {
+	switch {
+	case daysUntilDeadline <= 0:
+		return SeverityToday
+	case daysUntilDeadline == 1:
+		return SeverityTomorrow
+	default:
+		return SeverityLater
+	}
+}
+
+// Channel は名前付きの通知先と、そこへ送るタスクの条件。
+// Rules が空の場合はすべての通知を受け取る。
+type Channel struct {
+	Name     string
+	Notifier Notifier
+	Rules    []Rule
+}
+
+// Rule は通知の振り分け条件。指定した項目はすべて満たす必要があり、空の項目は条件にしない。
+type Rule struct {
+	Kinds      []Kind
+	Projects   []string
+	TaskTypes  []string
+	Severities []Severity
+}
+
+// Accepts は、いずれかのルールに一致すれば true を返す。
+func (c Channel) Accepts(kind Kind, t *task.Task, severity Severity) bool {
+	if len(c.Rules) == 0 {
+		return true
+	}
+	for _, r := range c.Rules {
+		if r.Matches(kind, t, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) Matches(kind Kind, t *task.Task, severity Severity) bool {
+	return matchAny(r.Kinds, kind) &&
+		matchAny(r.Projects, t.ProjectName) &&
+		matchAny(r.TaskTypes, t.TaskType) &&
+		matchAny(r.Severities, severity)
+}
+
+func matchAny[T comparable](candidates []T, v T) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+	for _, c := range candidates {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryError は送信に失敗したチャネルとそのエラーをまとめたもの。
+type DeliveryError struct {
+	Failures map[string]error
+}
+
+func (e *DeliveryError) Error() string {
+	names := e.FailedChannels()
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", name, e.Failures[name]))
+	}
+	return fmt.Sprintf("failed to notify %d channel(s): %s", len(names), strings.Join(msgs, "; "))
+}
+
+// FailedChannels は失敗したチャネル名を名前順で返す。
+func (e *DeliveryError) FailedChannels() []string {
+	names := make([]string, 0, len(e.Failures))
+	for name := range e.Failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *DeliveryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, name := range e.FailedChannels() {
+		errs = append(errs, e.Failures[name])
+	}
+	return errs
+}
@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Color は通知先が色表現に対応している場合に使う RGB 値。
+type Color int
+
+const (
+	ColorNone   Color = 0
+	ColorRed    Color = 0xE74C3C
+	ColorOrange Color = 0xE67E22
+	ColorYellow Color = 0xF1C40F
+	ColorBlue   Color = 0x3498DB
+)
+
+// Message は通知先に依存しない構造化された通知内容。
+// Discord などは Sections を埋め込みとして描画し、テキストのみの通知先は Text() を使う。
+type Message struct {
+	// 先頭に付けるメンション（例: "@here"）。空の場合は付けない
+	Mention  string
+	Title    string
+	Sections []Section
+}
+
+// Section は同じ色で表示される項目のまとまり。
+type Section struct {
+	Title string
+	Color Color
+	Items []Item
+}
+
+// Item はタスク 1 件分の表示内容。
+type Item struct {
+	Name    string
+	URL     string
+	Project string
+	Detail  string
+	// 通知から直接タスクを更新するリンク（例: 完了にする）。対応している通知先だけが描画する
+	Actions []Action
+}
+
+// Action は通知に付けるタスク操作のリンク。
+type Action struct {
+	Label string
+	URL   string
+}
+
+// Text は Markdown 形式のテキストとして描画する。
+func (m *Message) Text() string {
+	var sb strings.Builder
+	if m.Mention != "" {
+		sb.WriteString(m.Mention + " ")
+	}
+	sb.WriteString(fmt.Sprintf("%s\n\n", m.Title))
+
+	for i, sec := range m.Sections {
+		if sec.Title != "" {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(fmt.Sprintf("**%s**\n", sec.Title))
+		}
+		for _, item := range sec.Items {
+			sb.WriteString(item.Text())
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// Text はリンクなしの 1 行として描画する。例: "- [Work] 資料作成: 🟠 明日締切"
+func (i Item) Text() string {
+	return fmt.Sprintf("- %s%s%s", i.projectPrefix(), i.Name, i.detailSuffix())
+}
+
+// Markdown は URL がある場合にタスク名をリンクにして 1 行で描画する。操作リンクがあれば末尾に付ける。
+func (i Item) Markdown() string {
+	name := i.Name
+	if i.URL != "" {
+		name = fmt.Sprintf("[%s](%s)", i.Name, i.URL)
+	}
+	return fmt.Sprintf("- %s%s%s%s", i.projectPrefix(), name, i.detailSuffix(), i.actionLinks())
+}
+
+func (i Item) projectPrefix() string {
+	if i.Project == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", i.Project)
+}
+
+func (i Item) detailSuffix() string {
+	if i.Detail == "" {
+		return ""
+	}
+	return ": " + i.Detail
+}
+
+// actionLinks は操作リンクを " [✅ 完了](...) · [📅 1日延期](...)" の形式で返す。
+func (i Item) actionLinks() string {
+	if len(i.Actions) == 0 {
+		return ""
+	}
+	links := make([]string, 0, len(i.Actions))
+	for _, a := range i.Actions {
+		links = append(links, fmt.Sprintf("[%s](%s)", a.Label, a.URL))
+	}
+	return " " + strings.Join(links, " · ")
+}
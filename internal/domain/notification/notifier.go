@@ -3,5 +3,23 @@ package notification
 import "context"
 
 type Notifier interface {
-	Notify(ctx context.Context, message string) error
+	Notify(ctx context.Context, message *Message) error
+}
+
+// TextNotifier はテキストしか送れない通知先。
+type TextNotifier interface {
+	NotifyText(ctx context.Context, text string) error
+}
+
+// TextOnly は TextNotifier を Notifier として扱う。Message は Text() で描画して送る。
+func TextOnly(n TextNotifier) Notifier {
+	return textOnly{n}
+}
+
+type textOnly struct {
+	TextNotifier
+}
+
+func (t textOnly) Notify(ctx context.Context, message *Message) error {
+	return t.NotifyText(ctx, message.Text())
 }
@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+// SnoozeScope は通知を止める対象の種類。
+type SnoozeScope string
+
+const (
+	// SnoozeTask はタスク ID が一致するタスクの通知を止める。
+	SnoozeTask SnoozeScope = "task"
+	// SnoozeProject はプロジェクト名が一致する（大文字小文字を区別しない）タスクの通知を止める。
+	SnoozeProject SnoozeScope = "project"
+)
+
+// Snooze は通知を止めるルール。
+type Snooze struct {
+	Scope SnoozeScope
+	// タスク ID またはプロジェクト名
+	Key string
+	// 表示用の名前（タスク名など）。空の場合は Key を使う
+	Label string
+	// この時刻まで止める。ゼロ値の場合は解除するまで止める（ミュート）
+	Until time.Time
+}
+
+// Active は now の時点でルールが有効かどうかを返す。
+func (s Snooze) Active(now time.Time) bool {
+	return s.Until.IsZero() || s.Until.After(now)
+}
+
+// Matches は t がこのルールの対象かどうかを返す。
+func (s Snooze) Matches(t *task.Task) bool {
+	switch s.Scope {
+	case SnoozeTask:
+		return t.ID == s.Key
+	case SnoozeProject:
+		return strings.EqualFold(t.ProjectName, s.Key)
+	default:
+		return false
+	}
+}
+
+// Name は表示用の名前を返す。
+func (s Snooze) Name() string {
+	if s.Label != "" {
+		return s.Label
+	}
+	return s.Key
+}
+
+// SnoozeStore は通知を止めるルールを保存する。Scope と Key の組で 1 件として扱う。
+type SnoozeStore interface {
+	// Snooze はルールを保存する。同じ Scope と Key のルールがある場合は上書きする。
+	// now の時点で期限切れのルールは削除してよい。
+	Snooze(ctx context.Context, s Snooze, now time.Time) error
+	// Unsnooze は Scope と Key が一致するルールを削除する。ルールがなかった場合は ok が false になる。
+	Unsnooze(ctx context.Context, scope SnoozeScope, key string) (ok bool, err error)
+	// Snoozes は now の時点で有効なルールを返す。
+	Snoozes(ctx context.Context, now time.Time) ([]Snooze, error)
+}
@@ -0,0 +1,21 @@
+package notification
+
+import (
+	"context"
+	"time"
+)
+
+// StateKey は送信済み状態を識別するキー。
+// 同じタスクでも緊急度（Bucket）が上がれば別のキーになり、再度通知される。
+type StateKey struct {
+	TaskID string
+	Kind   Kind
+	Bucket string
+}
+
+// StateStore は通知の送信済み状態を保存する。
+type StateStore interface {
+	// LastSent は key を最後に送信した時刻を返す。未送信の場合は ok が false になる。
+	LastSent(ctx context.Context, key StateKey) (sentAt time.Time, ok bool, err error)
+	MarkSent(ctx context.Context, keys []StateKey, sentAt time.Time) error
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// EscalationLadder は締切通知を送り直す段階。締切の Days 日前（降順でなくてもよい）の段を
+// またぐたびに通知し、同じ段にいる間は通知しない。
+// HourlyOnDueDay が true の場合、締切日は時刻付きのタスクについて締切までの残り時間が
+// 1 時間減るごとに段が進む（締切時刻の 1 時間前、2 時間前…をまたぐたびに通知される）。
+type EscalationLadder struct {
+	Days           []int
+	HourlyOnDueDay bool
+}
+
+// Rung は now の時点で t がいる段を返す。同じ段にいる間は同じ値になるため、送信済み状態のキーに使える。
+//   - "d3": 締切まで 3 日以内で、次の段（例: 1 日前）にはまだ達していない
+//   - "d0-h2": 締切日で、締切まで残り 1 時間超 2 時間以内（HourlyOnDueDay かつ時刻付きの場合のみ）。
+//     締切時刻を過ぎた場合は "d0-h0"
+//   - "early": はしごの最も大きい日数より前
+//
+// 締切日が未設定のタスクと、Days が空で時間単位の段にもいない場合は "" を返す
+// （呼び出し側は締切までの日数による緊急度などで代用する）。
+func (l EscalationLadder) Rung(t *Task, now time.Time) string {
+	if t.DueDate == nil {
+		return ""
+	}
+	days := t.DaysUntilDeadline(now)
+	if l.HourlyOnDueDay && days == 0 && t.DueHasTime {
+		remaining := t.DueDate.Sub(now)
+		hours := max(int((remaining+time.Hour-1)/time.Hour), 0)
+		return fmt.Sprintf("d0-h%d", hours)
+	}
+
+	if len(l.Days) == 0 {
+		return ""
+	}
+	rungs := slices.Clone(l.Days)
+	slices.Sort(rungs)
+	for _, r := range rungs {
+		if days <= r {
+			return fmt.Sprintf("d%d", r)
+		}
+	}
+	return "early"
+}
+
+// MaxDays は最も大きい段の日数を返す。Days が空の場合は 0。
+func (l EscalationLadder) MaxDays() int {
+	if len(l.Days) == 0 {
+		return 0
+	}
+	return slices.Max(l.Days)
+}
+
+// Includes は now の時点で t がいずれかの日数の段にいる（締切を過ぎておらず、最も大きい段の日数以内）かどうかを返す。
+// リードタイムより前の段でも通知されるよう、通知対象の判定に使う。
+func (l EscalationLadder) Includes(t *Task, now time.Time) bool {
+	return len(l.Days) > 0 && t.IsApproachingDeadline(now, l.MaxDays())
+}
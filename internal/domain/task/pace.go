@@ -0,0 +1,59 @@
+package task
+
+import "time"
+
+// DefaultPagesPerDay は設定がない場合の読書ペース。
+const DefaultPagesPerDay = 30
+
+// PaceMode は読書ペースの計算方法。
+type PaceMode string
+
+const (
+	// PaceFixed は締切から 1 日あたり PagesPerDay ページで逆算する。
+	PaceFixed PaceMode = "fixed"
+	// PaceLinear は開始日から締切まで均等に読み進める計画とする。開始日がない場合は PaceFixed と同じ。
+	PaceLinear PaceMode = "linear"
+)
+
+// Pace は読書タスクの目標ペース。ゼロ値は 1 日 DefaultPagesPerDay ページの固定ペース。
+type Pace struct {
+	Mode        PaceMode
+	PagesPerDay int
+}
+
+func (p Pace) pagesPerDay() int {
+	if p.PagesPerDay > 0 {
+		return p.PagesPerDay
+	}
+	return DefaultPagesPerDay
+}
+
+// 締切当日の終わりには TotalPages を読み終えている前提で、今日の終わりまでに読むべきページ数を返す。
+func (p Pace) expectedReadPages(t *Task, now time.Time) int {
+	loc := now.Location()
+	daysUntilDue := daysBetween(now, *t.DueDate, loc)
+	if daysUntilDue < 0 {
+		// 締切を過ぎている場合は完了しているべき
+		return t.TotalPages
+	}
+
+	var expected int
+	if p.Mode == PaceLinear && t.StartDate != nil {
+		totalDays := daysBetween(*t.StartDate, *t.DueDate, loc) + 1
+		elapsedDays := daysBetween(*t.StartDate, now, loc) + 1
+		if totalDays <= 0 {
+			return t.TotalPages
+		}
+		expected = t.TotalPages * elapsedDays / totalDays
+	} else {
+		expected = t.TotalPages - (daysUntilDue * p.pagesPerDay())
+	}
+
+	if expected < 0 {
+		return 0
+	}
+	if expected > t.TotalPages {
+		return t.TotalPages
+	}
+	return expected
+}
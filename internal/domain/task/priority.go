@@ -0,0 +1,98 @@
+package task
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+)
+
+// Priority はタスクの優先度。値が大きいほど優先度が高い。
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = map[Priority]string{
+	PriorityNone:   "none",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// ParsePriority は "high", "medium", "low", "none" を Priority に変換する。
+func ParsePriority(s string) (Priority, error) {
+	for p, name := range priorityNames {
+		if name == s {
+			return p, nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("unknown priority %q", s)
+}
+
+// CompareUrgency は now の時点で締切までの日数が少ない順、同じ日数なら優先度が高い順、
+// それも同じなら締切時刻が早い順に並べる比較関数を返す。
+// 締切を過ぎたタスクは超過日数が大きいものほど前になる。
+func CompareUrgency(now time.Time) func(a, b *Task) int {
+	return func(a, b *Task) int {
+		return cmp.Or(
+			cmp.Compare(a.DaysUntilDeadline(now), b.DaysUntilDeadline(now)),
+			cmp.Compare(b.Priority, a.Priority),
+			compareDueDate(a, b),
+		)
+	}
+}
+
+func compareDueDate(a, b *Task) int {
+	if a.DueDate == nil || b.DueDate == nil {
+		return 0
+	}
+	return a.DueDate.Compare(*b.DueDate)
+}
+
+// LeadTimes は締切の何日前から通知するかを優先度ごとに決める。
+// ByPriority にない優先度は Default を使う。
+// Hours が 0 より大きい場合、時刻付きの締切のタスクは締切の Hours 時間前からも通知対象になる。
+type LeadTimes struct {
+	Default    int
+	ByPriority map[Priority]int
+	Hours      int
+}
+
+// Includes は now の時点で t がリードタイム内（日数または時間）にあるかどうかを返す。
+func (l LeadTimes) Includes(t *Task, now time.Time) bool {
+	return t.IsApproachingDeadline(now, l.DaysFor(t.Priority)) || l.IsImminent(t, now)
+}
+
+// IsImminent は now の時点で時刻付きの締切まで Hours 時間以内かどうかを返す。
+func (l LeadTimes) IsImminent(t *Task, now time.Time) bool {
+	return l.Hours > 0 && t.IsDueWithin(now, time.Duration(l.Hours)*time.Hour)
+}
+
+// DaysFor は優先度 p のタスクを何日前から通知するかを返す。
+func (l LeadTimes) DaysFor(p Priority) int {
+	if days, ok := l.ByPriority[p]; ok {
+		return days
+	}
+	return l.Default
+}
+
+// Max はいずれかの優先度で通知対象になりうる最大の日数を返す。取得範囲の決定に使う。
+func (l LeadTimes) Max() int {
+	m := l.Default
+	for _, days := range l.ByPriority {
+		m = max(m, days)
+	}
+	// 日付をまたぐ時間指定（例: 翌日 01:00 締切の 2 時間前）も取得範囲に含める
+	return max(m, (l.Hours+23)/24)
+}
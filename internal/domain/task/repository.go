@@ -1,8 +1,22 @@
 package task
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	FetchTasksWithUpcomingDeadlines(ctx context.Context, daysBeforeDeadline int) ([]*Task, error)
 	FetchIncompleteStudyTasks(ctx context.Context) ([]*Task, error)
+	// 締切を過ぎた未完了タスクを返す。maxDaysOverdue が 0 より大きい場合はその日数より前の締切を除く。
+	FetchOverdueTasks(ctx context.Context, maxDaysOverdue int) ([]*Task, error)
+	// FetchTask は ID のタスクを 1 件返す。
+	FetchTask(ctx context.Context, taskID string) (*Task, error)
+
+	// UpdateStatus はタスクのステータスを status に変更する。
+	UpdateStatus(ctx context.Context, taskID string, status Status) error
+	// UpdateReadPages は読んだページ数を readPages に変更する。
+	UpdateReadPages(ctx context.Context, taskID string, readPages int) error
+	// PostponeDue は締切を due に変更する。hasTime が false の場合は日付のみを設定する。
+	PostponeDue(ctx context.Context, taskID string, due time.Time, hasTime bool) error
 }
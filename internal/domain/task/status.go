@@ -0,0 +1,37 @@
+package task
+
+// StatusGroup は Notion のステータスグループに対応するステータスの大分類。
+// Status の名前はデータベースごとに自由に付けられるため、通知対象かどうかはこちらで判定する。
+type StatusGroup string
+
+const (
+	StatusGroupToDo       StatusGroup = "to_do"
+	StatusGroupInProgress StatusGroup = "in_progress"
+	StatusGroupComplete   StatusGroup = "complete"
+)
+
+// DefaultStatusGroup は既定の 4 つの Status をグループに対応付ける。
+// それ以外の Status は未完了として扱うため StatusGroupToDo を返す。
+func DefaultStatusGroup(s Status) StatusGroup {
+	switch s {
+	case StatusInProgress:
+		return StatusGroupInProgress
+	case StatusDone, StatusArchived:
+		return StatusGroupComplete
+	default:
+		return StatusGroupToDo
+	}
+}
+
+// Group は Status の属するグループを返す。StatusGroup が未設定の場合は DefaultStatusGroup で判定する。
+func (t *Task) Group() StatusGroup {
+	if t.StatusGroup != "" {
+		return t.StatusGroup
+	}
+	return DefaultStatusGroup(t.Status)
+}
+
+// IsComplete は完了グループのステータスかどうかを返す。
+func (t *Task) IsComplete() bool {
+	return t.Group() == StatusGroupComplete
+}
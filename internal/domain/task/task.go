@@ -2,6 +2,7 @@ package task
 
 import "time"
 
+// Status は Notion のステータス名そのもの。データベースによって "未着手" や "Blocked" なども入る。
 type Status string
 
 const (
@@ -17,11 +18,22 @@ type Task struct {
 	ProjectName string
 	DueDate     *time.Time
 	Status      Status
+	// Status の属するグループ。空の場合は DefaultStatusGroup で判定する
+	StatusGroup StatusGroup
+	Priority    Priority
+	URL         string
+	// DueDate が時刻付きで設定されているかどうか。false の場合は締切日の 00:00 が入っている
+	DueHasTime bool
+	// Notion のチェックボックス（例: 「通知しない」）で通知を止めているかどうか
+	Muted bool
 	// Reading specific properties
 	TaskType   string
 	StartDate  *time.Time
 	TotalPages int
 	ReadPages  int
+	// 読書ペース。PagesPerDay（Notion のタスクごとの設定）が 0 より大きい場合はそちらを優先する
+	Pace        Pace
+	PagesPerDay int
 }
 
 func NewTask(id, name, projectName string, dueDate *time.Time, status Status) *Task {
@@ -34,75 +46,141 @@ func NewTask(id, name, projectName string, dueDate *time.Time, status Status) *T
 	}
 }
 
-func (t *Task) ExpectedReadPages() int {
+// 日数に関するメソッドは、呼び出し側（アプリケーション層）が時計から得た現在時刻 now を基準に計算する。
+// 日付の区切りは now のタイムゾーンで数えるため、now は設定されたタイムゾーン（notification.timezone）で渡すこと。
+
+func (t *Task) ExpectedReadPages(now time.Time) int {
 	if t.DueDate == nil || t.TotalPages == 0 {
 		return 0
 	}
+	return t.effectivePace().expectedReadPages(t, now)
+}
+
+// RequiredPagesPerDay は締切当日までに読み終えるために、今日から 1 日あたり何ページ読む必要があるかを返す。
+// 締切を過ぎている場合は残りページ数をそのまま返す。
+func (t *Task) RequiredPagesPerDay(now time.Time) int {
+	if t.DueDate == nil || t.TotalPages == 0 {
+		return 0
+	}
+	remaining := t.TotalPages - t.ReadPages
+	if remaining <= 0 {
+		return 0
+	}
 
-	daysUntilDue := t.daysUntilDeadline()
-	if daysUntilDue < 0 {
-		// 締切を過ぎている場合は完了しているべき
-		return t.TotalPages
+	daysLeft := t.DaysUntilDeadline(now) + 1
+	if daysLeft <= 0 {
+		return remaining
 	}
+	return (remaining + daysLeft - 1) / daysLeft
+}
 
-	// 1日30ページペースで逆算
-	// 締切当日は TotalPages である必要がある
-	expected := t.TotalPages - (daysUntilDue * 30)
+// ProjectedFinishDate は開始日からの実績ペースで読み進めた場合に読み終える日を返す。
+// 開始日がない、または 1 ページも読んでいない場合は見込みが立たないため nil を返す。
+func (t *Task) ProjectedFinishDate(now time.Time) *time.Time {
+	if t.StartDate == nil || t.TotalPages == 0 || t.ReadPages <= 0 {
+		return nil
+	}
 
-	if expected < 0 {
-		return 0
+	elapsedDays := daysBetween(*t.StartDate, now, now.Location()) + 1
+	if elapsedDays <= 0 {
+		return nil
 	}
-	if expected > t.TotalPages {
-		return t.TotalPages
+
+	remaining := t.TotalPages - t.ReadPages
+	if remaining <= 0 {
+		return &now
+	}
+	// 残りページ ÷ (読んだページ / 経過日数) を切り上げ
+	daysNeeded := (remaining*elapsedDays + t.ReadPages - 1) / t.ReadPages
+	finish := now.AddDate(0, 0, daysNeeded)
+	return &finish
+}
+
+func (t *Task) effectivePace() Pace {
+	if t.PagesPerDay > 0 {
+		return Pace{Mode: PaceFixed, PagesPerDay: t.PagesPerDay}
 	}
-	return expected
+	return t.Pace
 }
 
-func (t *Task) IsReadingPaceDelayed() bool {
+func (t *Task) IsReadingPaceDelayed(now time.Time) bool {
 	if t.TaskType != "Study" || t.DueDate == nil || t.TotalPages == 0 {
 		return false
 	}
-	if t.Status == StatusDone || t.Status == StatusArchived {
+	if t.IsComplete() {
 		return false
 	}
-	return t.ReadPages < t.ExpectedReadPages()
+	return t.ReadPages < t.ExpectedReadPages(now)
 }
 
-func (t *Task) IsApproachingDeadline(daysBeforeDeadline int) bool {
-	days := t.daysUntilDeadline()
+// ReadingProgress は読了率（0〜100 の %）を返す。総ページ数が未設定の場合は 0。
+func (t *Task) ReadingProgress() int {
+	if t.TotalPages <= 0 {
+		return 0
+	}
+	return min(max(t.ReadPages, 0)*100/t.TotalPages, 100)
+}
+
+func (t *Task) IsApproachingDeadline(now time.Time, daysBeforeDeadline int) bool {
+	days := t.DaysUntilDeadline(now)
 	if days < 0 {
 		return false
 	}
 	return days <= daysBeforeDeadline
 }
 
-func (t *Task) IsOverdue() bool {
+func (t *Task) IsOverdue(now time.Time) bool {
 	if t.DueDate == nil {
 		return false
 	}
-	return t.daysUntilDeadline() < 0
+	return t.DaysUntilDeadline(now) < 0
+}
+
+// TimeUntilDeadline は時刻付きの締切までの残り時間を返す。締切時刻を過ぎている場合は負の値。
+// 締切が日付のみ、または未設定の場合は ok が false になる。
+func (t *Task) TimeUntilDeadline(now time.Time) (remaining time.Duration, ok bool) {
+	if t.DueDate == nil || !t.DueHasTime {
+		return 0, false
+	}
+	return t.DueDate.Sub(now), true
 }
 
-// Returns -1 if no due date is set.
-func (t *Task) DaysUntilDeadline() int {
-	return t.daysUntilDeadline()
+// IsDueWithin は時刻付きの締切まで d 以内で、締切時刻をまだ過ぎていないかどうかを返す。
+func (t *Task) IsDueWithin(now time.Time, d time.Duration) bool {
+	remaining, ok := t.TimeUntilDeadline(now)
+	return ok && remaining >= 0 && remaining <= d
 }
 
+// 締切を何日過ぎているかを返す。締切前または締切未設定の場合は 0。
+func (t *Task) DaysOverdue(now time.Time) int {
+	if !t.IsOverdue(now) {
+		return 0
+	}
+	return -t.DaysUntilDeadline(now)
+}
+
+// 完了グループ以外のステータスのタスクを通知対象とする。
 func (t *Task) IsNotificationTarget() bool {
-	return t.Status == StatusNotStarted || t.Status == StatusInProgress
+	return !t.IsComplete()
 }
 
+// DaysUntilDeadline は now から締切までの日数を返す。締切が未設定の場合は -1。
 // Due date は日付のみ（"YYYY-MM-DD"）または時刻付き（RFC3339）で返される。
-// 日単位で比較するため、年月日のみを抽出して日数差を計算する。
-func (t *Task) daysUntilDeadline() int {
+// 日単位で比較するため、now のタイムゾーンでの年月日のみを抽出して日数差を計算する。
+func (t *Task) DaysUntilDeadline(now time.Time) int {
 	if t.DueDate == nil {
 		return -1
 	}
-	dueY, dueM, dueD := t.DueDate.Date()
-	nowY, nowM, nowD := time.Now().Date()
+	return daysBetween(now, *t.DueDate, now.Location())
+}
+
+// daysBetween は from から to までの日数を、loc における年月日のみで計算する。
+func daysBetween(from, to time.Time, loc *time.Location) int {
+	fromY, fromM, fromD := from.In(loc).Date()
+	toY, toM, toD := to.In(loc).Date()
 
-	dueDate := time.Date(dueY, dueM, dueD, 0, 0, 0, 0, time.UTC)
-	today := time.Date(nowY, nowM, nowD, 0, 0, 0, 0, time.UTC)
+	fromDate := time.Date(fromY, fromM, fromD, 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(toY, toM, toD, 0, 0, 0, 0, time.UTC)
 
-	return int(dueDate.Sub(today).Hours() / 24)
+	return int(toDate.Sub(fromDate).Hours() / 24)
 }
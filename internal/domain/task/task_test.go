@@ -1,6 +1,7 @@
 package task
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -53,7 +54,7 @@ func TestTask_IsApproachingDeadline(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			task := NewTask("1", "Test Task", "Test Project", tt.dueDate, StatusNotStarted)
-			got := task.IsApproachingDeadline(tt.daysBeforeDeadline)
+			got := task.IsApproachingDeadline(time.Now(), tt.daysBeforeDeadline)
 			if got != tt.want {
 				t.Errorf("IsApproachingDeadline() = %v, want %v", got, tt.want)
 			}
@@ -92,7 +93,7 @@ func TestTask_IsOverdue(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			task := NewTask("1", "Test Task", "Test Project", tt.dueDate, StatusNotStarted)
-			got := task.IsOverdue()
+			got := task.IsOverdue(time.Now())
 			if got != tt.want {
 				t.Errorf("IsOverdue() = %v, want %v", got, tt.want)
 			}
@@ -100,6 +101,29 @@ func TestTask_IsOverdue(t *testing.T) {
 	}
 }
 
+func TestTask_DaysOverdue(t *testing.T) {
+	tests := []struct {
+		name    string
+		dueDate *time.Time
+		want    int
+	}{
+		{name: "no due date", dueDate: nil, want: 0},
+		{name: "due today", dueDate: timePtr(time.Now()), want: 0},
+		{name: "due tomorrow", dueDate: timePtr(time.Now().AddDate(0, 0, 1)), want: 0},
+		{name: "due yesterday", dueDate: timePtr(time.Now().AddDate(0, 0, -1)), want: 1},
+		{name: "due 5 days ago", dueDate: timePtr(time.Now().AddDate(0, 0, -5)), want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("1", "Test Task", "Test Project", tt.dueDate, StatusNotStarted)
+			if got := task.DaysOverdue(time.Now()); got != tt.want {
+				t.Errorf("DaysOverdue() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTask_IsReadingPaceDelayed(t *testing.T) {
 	today := time.Now().Truncate(24 * time.Hour)
 
@@ -186,7 +210,7 @@ func TestTask_IsReadingPaceDelayed(t *testing.T) {
 				ReadPages:  tt.readPages,
 				Status:     tt.status,
 			}
-			got := task.IsReadingPaceDelayed()
+			got := task.IsReadingPaceDelayed(time.Now())
 			if got != tt.want {
 				t.Errorf("IsReadingPaceDelayed() = %v, want %v", got, tt.want)
 			}
@@ -203,35 +227,180 @@ func TestTask_ExpectedReadPages(t *testing.T) {
 		TotalPages: 100,
 	}
 
-	expected := task.ExpectedReadPages()
+	expected := task.ExpectedReadPages(time.Now())
 	// Tomorrow is 1 day until due. 100 - (1 * 30) = 70.
 	if expected != 70 {
 		t.Errorf("ExpectedReadPages() = %d, want %d", expected, 70)
 	}
 
 	task.DueDate = &today
-	expected = task.ExpectedReadPages()
+	expected = task.ExpectedReadPages(time.Now())
 	// Today is 0 days until due. 100 - (0 * 30) = 100.
 	if expected != 100 {
 		t.Errorf("ExpectedReadPages() = %d, want %d", expected, 100)
 	}
 }
 
+func TestTask_ExpectedReadPages_Pace(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		pace        Pace
+		pagesPerDay int
+		startDate   *time.Time
+		dueDate     time.Time
+		want        int
+	}{
+		{
+			name:    "fixed 10 pages/day, due in 3 days",
+			pace:    Pace{Mode: PaceFixed, PagesPerDay: 10},
+			dueDate: now.AddDate(0, 0, 3),
+			want:    70,
+		},
+		{
+			name:        "per-task pages/day overrides global pace",
+			pace:        Pace{Mode: PaceFixed, PagesPerDay: 10},
+			pagesPerDay: 50,
+			dueDate:     now.AddDate(0, 0, 1),
+			want:        50,
+		},
+		{
+			// 10 日間（開始日〜締切日）で 100 ページ、今日が 5 日目
+			name:      "linear plan, halfway",
+			pace:      Pace{Mode: PaceLinear},
+			startDate: timePtr(now.AddDate(0, 0, -4)),
+			dueDate:   now.AddDate(0, 0, 5),
+			want:      50,
+		},
+		{
+			name:      "linear plan, not started yet",
+			pace:      Pace{Mode: PaceLinear},
+			startDate: timePtr(now.AddDate(0, 0, 2)),
+			dueDate:   now.AddDate(0, 0, 10),
+			want:      0,
+		},
+		{
+			name:    "linear plan without start date falls back to fixed default",
+			pace:    Pace{Mode: PaceLinear},
+			dueDate: now.AddDate(0, 0, 1),
+			want:    70,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				DueDate:     &tt.dueDate,
+				StartDate:   tt.startDate,
+				TotalPages:  100,
+				Pace:        tt.pace,
+				PagesPerDay: tt.pagesPerDay,
+			}
+			if got := task.ExpectedReadPages(time.Now()); got != tt.want {
+				t.Errorf("ExpectedReadPages() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_RequiredPagesPerDay(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		dueDate   time.Time
+		readPages int
+		want      int
+	}{
+		{name: "due today", dueDate: now, readPages: 70, want: 30},
+		{name: "due in 3 days (4 days left including today)", dueDate: now.AddDate(0, 0, 3), readPages: 50, want: 13},
+		{name: "already finished", dueDate: now.AddDate(0, 0, 3), readPages: 100, want: 0},
+		{name: "overdue", dueDate: now.AddDate(0, 0, -2), readPages: 40, want: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{DueDate: &tt.dueDate, TotalPages: 100, ReadPages: tt.readPages}
+			if got := task.RequiredPagesPerDay(now); got != tt.want {
+				t.Errorf("RequiredPagesPerDay() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_ReadingProgress(t *testing.T) {
+	tests := []struct {
+		name       string
+		totalPages int
+		readPages  int
+		want       int
+	}{
+		{name: "halfway", totalPages: 300, readPages: 150, want: 50},
+		{name: "rounds down", totalPages: 300, readPages: 100, want: 33},
+		{name: "not started", totalPages: 300, readPages: 0, want: 0},
+		{name: "read more than total", totalPages: 300, readPages: 320, want: 100},
+		{name: "total pages not set", totalPages: 0, readPages: 50, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{TotalPages: tt.totalPages, ReadPages: tt.readPages}
+			if got := task.ReadingProgress(); got != tt.want {
+				t.Errorf("ReadingProgress() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_ProjectedFinishDate(t *testing.T) {
+	now := time.Now()
+
+	// 5 日間で 50 ページ（1 日 10 ページ）、残り 50 ページは 5 日後に読み終える見込み
+	task := &Task{
+		StartDate:  timePtr(now.AddDate(0, 0, -4)),
+		TotalPages: 100,
+		ReadPages:  50,
+	}
+	got := task.ProjectedFinishDate(now)
+	if got == nil {
+		t.Fatal("expected projected finish date")
+	}
+	if want := now.AddDate(0, 0, 5); got.Format("2006-01-02") != want.Format("2006-01-02") {
+		t.Errorf("ProjectedFinishDate() = %s, want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+
+	task.ReadPages = 0
+	if got := task.ProjectedFinishDate(now); got != nil {
+		t.Errorf("expected nil when nothing has been read, got %v", got)
+	}
+
+	task.ReadPages = 10
+	task.StartDate = nil
+	if got := task.ProjectedFinishDate(now); got != nil {
+		t.Errorf("expected nil without start date, got %v", got)
+	}
+}
+
 func TestTask_IsNotificationTarget(t *testing.T) {
 	tests := []struct {
 		name   string
 		status Status
+		group  StatusGroup
 		want   bool
 	}{
 		{name: "NotStarted is target", status: StatusNotStarted, want: true},
 		{name: "InProgress is target", status: StatusInProgress, want: true},
 		{name: "Done is not target", status: StatusDone, want: false},
 		{name: "Archived is not target", status: StatusArchived, want: false},
+		{name: "unknown status defaults to to_do", status: "Blocked", want: true},
+		{name: "custom status in complete group", status: "完了", group: StatusGroupComplete, want: false},
+		{name: "custom status in to_do group", status: "未着手", group: StatusGroupToDo, want: true},
+		{name: "group overrides default mapping", status: StatusDone, group: StatusGroupInProgress, want: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			task := NewTask("1", "Test Task", "Test Project", nil, tt.status)
+			task.StatusGroup = tt.group
 			got := task.IsNotificationTarget()
 			if got != tt.want {
 				t.Errorf("IsNotificationTarget() = %v, want %v", got, tt.want)
@@ -240,6 +409,243 @@ func TestTask_IsNotificationTarget(t *testing.T) {
 	}
 }
 
+func TestDaysBetween_Timezone(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, jst)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		// UTC のコンテナで JST 00:00〜09:00 の間は UTC の日付が前日になる
+		{name: "JST 08:59 on due date (UTC previous day)", now: time.Date(2026, 2, 28, 23, 59, 0, 0, time.UTC), want: 0},
+		{name: "JST 09:00 on due date", now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), want: 0},
+		{name: "JST 23:59 the day before", now: time.Date(2026, 2, 28, 14, 59, 0, 0, time.UTC), want: 1},
+		{name: "JST 00:00 the day after", now: time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC), want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysBetween(tt.now, due, jst); got != tt.want {
+				t.Errorf("daysBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_DaysUntilDeadline_FixedClock(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, jst)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{name: "one second before midnight the day before", now: time.Date(2026, 2, 28, 23, 59, 59, 0, jst), want: 1},
+		{name: "midnight on due date", now: time.Date(2026, 3, 1, 0, 0, 0, 0, jst), want: 0},
+		{name: "last second of due date", now: time.Date(2026, 3, 1, 23, 59, 59, 0, jst), want: 0},
+		{name: "midnight the day after", now: time.Date(2026, 3, 2, 0, 0, 0, 0, jst), want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("1", "Test Task", "Test Project", &due, StatusNotStarted)
+			if got := task.DaysUntilDeadline(tt.now); got != tt.want {
+				t.Errorf("DaysUntilDeadline() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareUrgency(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newTask := func(id string, dueOffset int, priority Priority) *Task {
+		due := time.Date(2026, 3, 1+dueOffset, 0, 0, 0, 0, time.UTC)
+		task := NewTask(id, id, "Work", &due, StatusNotStarted)
+		task.Priority = priority
+		return task
+	}
+
+	tasks := []*Task{
+		newTask("tomorrow-low", 1, PriorityLow),
+		newTask("today-none", 0, PriorityNone),
+		newTask("tomorrow-high", 1, PriorityHigh),
+		newTask("overdue", -2, PriorityLow),
+		newTask("today-medium", 0, PriorityMedium),
+	}
+	// 同じ日・同じ優先度なら締切時刻が早い順
+	evening := newTask("today-none-18:00", 0, PriorityNone)
+	*evening.DueDate = evening.DueDate.Add(18 * time.Hour)
+	morning := newTask("today-none-10:00", 0, PriorityNone)
+	*morning.DueDate = morning.DueDate.Add(10 * time.Hour)
+	tasks = append(tasks, evening, morning)
+	slices.SortStableFunc(tasks, CompareUrgency(now))
+
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.ID)
+	}
+	want := []string{"overdue", "today-medium", "today-none", "today-none-10:00", "today-none-18:00", "tomorrow-high", "tomorrow-low"}
+	if !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestLeadTimes(t *testing.T) {
+	lead := LeadTimes{Default: 3, ByPriority: map[Priority]int{PriorityHigh: 7, PriorityLow: 1}}
+
+	tests := []struct {
+		priority Priority
+		want     int
+	}{
+		{priority: PriorityHigh, want: 7},
+		{priority: PriorityMedium, want: 3},
+		{priority: PriorityLow, want: 1},
+		{priority: PriorityNone, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority.String(), func(t *testing.T) {
+			if got := lead.DaysFor(tt.priority); got != tt.want {
+				t.Errorf("DaysFor(%s) = %d, want %d", tt.priority, got, tt.want)
+			}
+		})
+	}
+
+	if got := lead.Max(); got != 7 {
+		t.Errorf("Max() = %d, want 7", got)
+	}
+	if got := (LeadTimes{Default: 0, Hours: 2}).Max(); got != 1 {
+		t.Errorf("Max() with Hours = %d, want 1", got)
+	}
+}
+
+func TestTask_IsDueWithin(t *testing.T) {
+	now := time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		due     time.Time
+		hasTime bool
+		want    bool
+	}{
+		{name: "within window across midnight", due: due, hasTime: true, want: true},
+		{name: "outside window", due: due.Add(time.Hour), hasTime: true, want: false},
+		{name: "past due time", due: now.Add(-time.Minute), hasTime: true, want: false},
+		{name: "date only", due: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), hasTime: false, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("1", "Test Task", "Test Project", &tt.due, StatusNotStarted)
+			task.DueHasTime = tt.hasTime
+			if got := task.IsDueWithin(now, 2*time.Hour); got != tt.want {
+				t.Errorf("IsDueWithin(2h) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, p := range []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh} {
+		got, err := ParsePriority(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParsePriority(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Error("expected error for unknown priority")
+	}
+}
+
+func TestEscalationLadder_Rung(t *testing.T) {
+	now := time.Date(2026, 3, 1, 13, 20, 0, 0, time.UTC)
+	ladder := EscalationLadder{Days: []int{7, 3, 1, 0}, HourlyOnDueDay: true}
+
+	tests := []struct {
+		name    string
+		due     *time.Time
+		hasTime bool
+		want    string
+	}{
+		{name: "before the ladder", due: timePtr(now.AddDate(0, 0, 10)), want: "early"},
+		{name: "7 days before", due: timePtr(now.AddDate(0, 0, 7)), want: "d7"},
+		{name: "between 7 and 3", due: timePtr(now.AddDate(0, 0, 5)), want: "d7"},
+		{name: "2 days before stays on the 3 day rung", due: timePtr(now.AddDate(0, 0, 2)), want: "d3"},
+		{name: "1 day before", due: timePtr(now.AddDate(0, 0, 1)), want: "d1"},
+		{name: "due day without time", due: timePtr(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)), want: "d0"},
+		{name: "due day with time", due: timePtr(time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)), hasTime: true, want: "d0-h5"},
+		{name: "due time within the hour", due: timePtr(time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)), hasTime: true, want: "d0-h1"},
+		{name: "due time passed", due: timePtr(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)), hasTime: true, want: "d0-h0"},
+		{name: "no due date", due: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("1", "Test Task", "Test Project", tt.due, StatusNotStarted)
+			task.DueHasTime = tt.hasTime
+			if got := ladder.Rung(task, now); got != tt.want {
+				t.Errorf("Rung() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// HourlyOnDueDay が false なら時刻付きでも日単位の段になる
+	daily := EscalationLadder{Days: []int{3, 0}}
+	task := NewTask("1", "Test Task", "Test Project", timePtr(time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)), StatusNotStarted)
+	task.DueHasTime = true
+	if got := daily.Rung(task, now); got != "d0" {
+		t.Errorf("Rung() without hourly = %q, want d0", got)
+	}
+
+	// Days がない場合は締切日の時間単位の段だけを返す
+	hourly := EscalationLadder{HourlyOnDueDay: true}
+	if got := hourly.Rung(task, now); got != "d0-h5" {
+		t.Errorf("Rung() hourly only on the due day = %q, want d0-h5", got)
+	}
+	task.DueDate = timePtr(now.AddDate(0, 0, 1))
+	if got := hourly.Rung(task, now); got != "" {
+		t.Errorf("Rung() hourly only before the due day = %q, want empty", got)
+	}
+}
+
+func TestEscalationLadder_Includes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	ladder := EscalationLadder{Days: []int{3, 7, 0}}
+	if got := ladder.MaxDays(); got != 7 {
+		t.Errorf("MaxDays() = %d, want 7", got)
+	}
+
+	tests := []struct {
+		name   string
+		ladder EscalationLadder
+		due    *time.Time
+		want   bool
+	}{
+		{name: "on the largest rung", ladder: ladder, due: timePtr(now.AddDate(0, 0, 7)), want: true},
+		{name: "before the ladder", ladder: ladder, due: timePtr(now.AddDate(0, 0, 8)), want: false},
+		{name: "overdue", ladder: ladder, due: timePtr(now.AddDate(0, 0, -1)), want: false},
+		{name: "no due date", ladder: ladder, due: nil, want: false},
+		{name: "hourly only", ladder: EscalationLadder{HourlyOnDueDay: true}, due: timePtr(now), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("1", "Test Task", "Test Project", tt.due, StatusNotStarted)
+			if got := tt.ladder.Includes(task, now); got != tt.want {
+				t.Errorf("Includes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
@@ -0,0 +1,115 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+const defaultAPIBaseURL = "https://discord.com/api/v10"
+
+// BotClient はボットのトークンで Discord API を呼び出す。スラッシュコマンドの登録に使う。
+type BotClient struct {
+	httpClient    *http.Client
+	baseURL       string
+	applicationID string
+	token         string
+	retry         httpretry.Policy
+}
+
+func NewBotClient(applicationID, token string) *BotClient {
+	return &BotClient{
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		baseURL:       defaultAPIBaseURL,
+		applicationID: applicationID,
+		token:         token,
+		retry:         httpretry.DefaultPolicy(),
+	}
+}
+
+type applicationCommand struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Options     []applicationCommandOption `json:"options,omitempty"`
+}
+
+type applicationCommandOption struct {
+	Type        int    `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+	MinValue    *int   `json:"min_value,omitempty"`
+}
+
+const (
+	optionTypeSubcommand = 1
+	optionTypeString     = 3
+	optionTypeInteger    = 4
+)
+
+// commandDefinitions は InteractionHandler が応答するコマンド。
+func commandDefinitions() []applicationCommand {
+	one := 1
+	return []applicationCommand{
+		{
+			Name:        "tasks",
+			Description: "締切が近いタスクを表示します",
+			Options: []applicationCommandOption{
+				{Type: optionTypeSubcommand, Name: "today", Description: "今日が締切のタスクと締切を過ぎたタスク"},
+				{Type: optionTypeSubcommand, Name: "week", Description: "今後7日間に締切があるタスク"},
+			},
+		},
+		{
+			Name:        "reading",
+			Description: "読書タスクの進捗を表示します",
+		},
+		{
+			Name:        "snooze",
+			Description: "タスクの通知を一時的に止めます",
+			Options: []applicationCommandOption{
+				{Type: optionTypeString, Name: "task", Description: "タスク名の一部、またはタスク ID", Required: true},
+				{Type: optionTypeInteger, Name: "days", Description: "止める日数（省略時 1 日）", MinValue: &one},
+			},
+		},
+	}
+}
+
+// RegisterCommands はスラッシュコマンドを登録する（既存の定義は置き換えられる）。
+// guildID を指定するとそのサーバーだけに登録し、すぐに反映される。空の場合はすべてのサーバー向けに登録する。
+func (c *BotClient) RegisterCommands(ctx context.Context, guildID string) error {
+	url := fmt.Sprintf("%s/applications/%s/commands", c.baseURL, c.applicationID)
+	if guildID != "" {
+		url = fmt.Sprintf("%s/applications/%s/guilds/%s/commands", c.baseURL, c.applicationID, guildID)
+	}
+	body, err := json.Marshal(commandDefinitions())
+	if err != nil {
+		return fmt.Errorf("failed to marshal commands: %w", err)
+	}
+
+	// 一括上書き（PUT）は冪等なので、5xx でも再試行できる
+	resp, err := c.retry.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bot "+c.token)
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register commands: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("discord API responded with status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+func TestBotClient_RegisterCommands(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotCommands []applicationCommand
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotCommands)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &BotClient{
+		httpClient:    server.Client(),
+		baseURL:       server.URL,
+		applicationID: "app-1",
+		token:         "bot-token",
+		retry:         httpretry.NoRetry(),
+	}
+	if err := client.RegisterCommands(context.Background(), "guild-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/applications/app-1/guilds/guild-1/commands" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "Bot bot-token" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	var names []string
+	for _, c := range gotCommands {
+		names = append(names, c.Name)
+	}
+	if len(names) != 3 || names[0] != "tasks" || names[1] != "reading" || names[2] != "snooze" {
+		t.Errorf("commands = %v", names)
+	}
+}
@@ -0,0 +1,333 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+// Discord はインタラクションに 3 秒以内の応答を求めるため、コマンドには「考え中」の応答を先に返し、
+// 結果は後から元の返信を編集して表示する。インタラクションのトークンは 15 分有効。
+const defaultCommandTimeout = 30 * time.Second
+
+// 署名のタイムスタンプがこれより古い（または未来の）リクエストは再送攻撃とみなして拒否する。
+const maxTimestampSkew = 5 * time.Minute
+
+const (
+	interactionTypePing               = 1
+	interactionTypeApplicationCommand = 2
+
+	responseTypePong                   = 1
+	responseTypeChannelMessage         = 4
+	responseTypeDeferredChannelMessage = 5
+
+	// 実行したユーザーにだけ表示される
+	messageFlagEphemeral = 1 << 6
+)
+
+// Commands はスラッシュコマンドに応答する。application.NotificationService が実装する。
+type Commands interface {
+	TasksToday(ctx context.Context) (*notification.Message, error)
+	TasksWeek(ctx context.Context) (*notification.Message, error)
+	Reading(ctx context.Context) (*notification.Message, error)
+	Snooze(ctx context.Context, query string, days int) (*notification.Message, error)
+}
+
+// InteractionHandler は Discord の Interactions Endpoint URL として登録する http.Handler。
+// リクエストの Ed25519 署名を検証し、/tasks today, /tasks week, /reading, /snooze に応答する。
+type InteractionHandler struct {
+	publicKey  ed25519.PublicKey
+	commands   Commands
+	timeout    time.Duration
+	httpClient *http.Client
+	baseURL    string
+	retry      httpretry.Policy
+	now        func() time.Time
+	// 実行中のコマンド（結果の送信まで）。closed が true になった後は増やさない
+	mu      sync.Mutex
+	closed  bool
+	running sync.WaitGroup
+}
+
+// publicKeyHex は Developer Portal の "Public Key"（16 進数）。
+func NewInteractionHandler(publicKeyHex string, commands Commands) (*InteractionHandler, error) {
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid discord public key: must be %d hex-encoded bytes", ed25519.PublicKeySize)
+	}
+	return &InteractionHandler{
+		publicKey:  ed25519.PublicKey(key),
+		commands:   commands,
+		timeout:    defaultCommandTimeout,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		baseURL:    defaultAPIBaseURL,
+		retry:      httpretry.DefaultPolicy(),
+		now:        time.Now,
+	}, nil
+}
+
+type interaction struct {
+	Type          int             `json:"type"`
+	ApplicationID string          `json:"application_id"`
+	Token         string          `json:"token"`
+	Data          interactionData `json:"data"`
+}
+
+type interactionData struct {
+	Name    string              `json:"name"`
+	Options []interactionOption `json:"options"`
+}
+
+// サブコマンドの場合は Options にその引数が入る。
+type interactionOption struct {
+	Name    string              `json:"name"`
+	Type    int                 `json:"type"`
+	Value   json.RawMessage     `json:"value"`
+	Options []interactionOption `json:"options"`
+}
+
+type interactionResponse struct {
+	Type int                      `json:"type"`
+	Data *interactionResponseData `json:"data,omitempty"`
+}
+
+type interactionResponseData struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []embed `json:"embeds,omitempty"`
+	Flags   int     `json:"flags,omitempty"`
+}
+
+func (h *InteractionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	// Discord は署名が不正なリクエストに 401 を返すことを確認してからエンドポイントを登録する
+	if !h.verify(r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var in interaction
+	if err := json.Unmarshal(body, &in); err != nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	switch in.Type {
+	case interactionTypePing:
+		writeInteractionResponse(w, interactionResponse{Type: responseTypePong})
+	case interactionTypeApplicationCommand:
+		run, err := h.parseCommand(in.Data)
+		if err != nil {
+			log.Printf("invalid discord command /%s: %v", in.Data.Name, err)
+			writeInteractionResponse(w, ephemeralResponse("⚠️ コマンドの指定が正しくありません"))
+			return
+		}
+		if !h.track() {
+			writeInteractionResponse(w, ephemeralResponse("⚠️ サーバーを停止しています。しばらくしてから再度お試しください"))
+			return
+		}
+		writeInteractionResponse(w, interactionResponse{Type: responseTypeDeferredChannelMessage})
+		go func() {
+			defer h.running.Done()
+			h.runDeferred(in, run)
+		}()
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+	}
+}
+
+// Shutdown は実行中のコマンドが結果を送信し終えるまで待つ。ctx が先に終わった場合は ctx.Err() を返す。
+// 呼び出した後に届いたコマンドは実行せず、停止中であることを返信する。
+func (h *InteractionHandler) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// track は Shutdown が始まっていなければ実行中のコマンドを 1 つ増やす。
+// Wait の最中に Add しないよう、closed と同じロックの中で増やす。
+func (h *InteractionHandler) track() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.running.Add(1)
+	return true
+}
+
+// verify は署名と、タイムスタンプ（Unix 秒）が maxTimestampSkew 以内であることを検証する。
+func (h *InteractionHandler) verify(signatureHex, timestamp string, body []byte) bool {
+	sig, err := hex.DecodeString(signatureHex)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := h.now().Sub(time.Unix(sec, 0)); skew > maxTimestampSkew || skew < -maxTimestampSkew {
+		return false
+	}
+	return ed25519.Verify(h.publicKey, append([]byte(timestamp), body...), sig)
+}
+
+type commandFunc func(ctx context.Context) (*notification.Message, error)
+
+// parseCommand はコマンドと引数を検証し、実行する関数を返す。
+func (h *InteractionHandler) parseCommand(data interactionData) (commandFunc, error) {
+	switch data.Name {
+	case "tasks":
+		if len(data.Options) == 0 {
+			return nil, fmt.Errorf("missing subcommand")
+		}
+		switch sub := data.Options[0].Name; sub {
+		case "today":
+			return h.commands.TasksToday, nil
+		case "week":
+			return h.commands.TasksWeek, nil
+		default:
+			return nil, fmt.Errorf("unknown subcommand: %s", sub)
+		}
+	case "reading":
+		return h.commands.Reading, nil
+	case "snooze":
+		var query string
+		var days int
+		for _, opt := range data.Options {
+			var err error
+			switch opt.Name {
+			case "task":
+				err = json.Unmarshal(opt.Value, &query)
+			case "days":
+				err = json.Unmarshal(opt.Value, &days)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid option %s: %w", opt.Name, err)
+			}
+		}
+		if query == "" {
+			return nil, fmt.Errorf("task is required")
+		}
+		return func(ctx context.Context) (*notification.Message, error) {
+			return h.commands.Snooze(ctx, query, days)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown command: %s", data.Name)
+	}
+}
+
+// runDeferred はコマンドを実行し、結果で「考え中」の返信を置き換える。
+// 失敗した場合は返信を削除し、実行したユーザーにだけエラーを表示する（内部のエラー内容は表示しない）。
+func (h *InteractionHandler) runDeferred(in interaction, run commandFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	original := fmt.Sprintf("%s/webhooks/%s/%s/messages/@original", h.baseURL, in.ApplicationID, in.Token)
+	msg, err := run(ctx)
+	if err == nil {
+		if err := h.send(ctx, http.MethodPatch, original, messageResponse(msg)); err != nil {
+			log.Printf("failed to send discord command /%s result: %v", in.Data.Name, err)
+		}
+		return
+	}
+
+	log.Printf("discord command /%s failed: %v", in.Data.Name, err)
+	text := "⚠️ コマンドを実行できませんでした。しばらくしてから再度お試しください"
+	if errors.Is(err, context.DeadlineExceeded) {
+		text = "⚠️ 時間内にタスクを取得できませんでした。しばらくしてから再度お試しください"
+	}
+	if err := h.send(ctx, http.MethodDelete, original, nil); err != nil {
+		log.Printf("failed to delete discord command /%s response: %v", in.Data.Name, err)
+	}
+	followup := fmt.Sprintf("%s/webhooks/%s/%s", h.baseURL, in.ApplicationID, in.Token)
+	if err := h.send(ctx, http.MethodPost, followup, ephemeralResponse(text).Data); err != nil {
+		log.Printf("failed to send discord command /%s error: %v", in.Data.Name, err)
+	}
+}
+
+// send はインタラクションのトークンで返信を編集・削除・追加する。data が nil の場合は本文なしで送る。
+func (h *InteractionHandler) send(ctx context.Context, method, url string, data *interactionResponseData) error {
+	var body []byte
+	if data != nil {
+		var err error
+		if body, err = json.Marshal(data); err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+	}
+	// 返信の追加（POST）は冪等ではないため、未処理が明らかな場合のみ再送される
+	resp, err := h.retry.Do(ctx, method != http.MethodPost, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if data != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return h.httpClient.Do(req)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("discord API responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func ephemeralResponse(text string) interactionResponse {
+	return interactionResponse{
+		Type: responseTypeChannelMessage,
+		Data: &interactionResponseData{Content: text, Flags: messageFlagEphemeral},
+	}
+}
+
+// messageResponse は Message を Webhook と同じ形式（本文にタイトル、セクションごとに埋め込み）にする。
+// 返信は 1 件しか編集しないため、上限を超える分は省略する。
+func messageResponse(msg *notification.Message) *interactionResponseData {
+	data := &interactionResponseData{Content: msg.Title}
+	if batches := splitEmbeds(buildEmbeds(msg.Sections)); len(batches) > 0 {
+		data.Embeds = batches[0]
+		if len(batches) > 1 {
+			data.Content += " (一部のみ表示)"
+		}
+	}
+	return data
+}
+
+func writeInteractionResponse(w http.ResponseWriter, resp interactionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("failed to write interaction response: %v", err)
+	}
+}
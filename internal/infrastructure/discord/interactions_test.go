@@ -0,0 +1,336 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+type fakeCommands struct {
+	snoozeQuery string
+	snoozeDays  int
+	err         error
+}
+
+func (f *fakeCommands) message(title string) (*notification.Message, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &notification.Message{
+		Title: title,
+		Sections: []notification.Section{{
+			Title: "本日締切",
+			Color: notification.ColorRed,
+			Items: []notification.Item{{Name: "資料作成", Project: "Work"}},
+		}},
+	}, nil
+}
+
+func (f *fakeCommands) TasksToday(ctx context.Context) (*notification.Message, error) {
+	return f.message("today")
+}
+
+func (f *fakeCommands) TasksWeek(ctx context.Context) (*notification.Message, error) {
+	return f.message("week")
+}
+
+func (f *fakeCommands) Reading(ctx context.Context) (*notification.Message, error) {
+	return f.message("reading")
+}
+
+func (f *fakeCommands) Snooze(ctx context.Context, query string, days int) (*notification.Message, error) {
+	f.snoozeQuery = query
+	f.snoozeDays = days
+	return f.message("snoozed")
+}
+
+// interactionClient は Discord の代わりに署名付きのインタラクションを送り、
+// 「考え中」の応答の後に送られる返信の編集などを受け取る。
+type interactionClient struct {
+	t       *testing.T
+	handler *InteractionHandler
+	key     ed25519.PrivateKey
+
+	mu       sync.Mutex
+	requests []webhookRequest
+}
+
+type webhookRequest struct {
+	method string
+	path   string
+	data   interactionResponseData
+}
+
+func newInteractionClient(t *testing.T, commands Commands) *interactionClient {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	h, err := NewInteractionHandler(hex.EncodeToString(pub), commands)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &interactionClient{t: t, handler: h, key: priv}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := webhookRequest{method: r.Method, path: r.URL.Path}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &req.data); err != nil {
+				t.Errorf("invalid webhook body: %v", err)
+			}
+		}
+		c.mu.Lock()
+		c.requests = append(c.requests, req)
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	h.baseURL = server.URL
+	h.httpClient = server.Client()
+	return c
+}
+
+func (c *interactionClient) send(body string, sign bool) *httptest.ResponseRecorder {
+	c.t.Helper()
+	return c.sendAt(body, sign, time.Now())
+}
+
+func (c *interactionClient) sendAt(body string, sign bool, at time.Time) *httptest.ResponseRecorder {
+	c.t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/discord/interactions", bytes.NewBufferString(body))
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	if sign {
+		sig := ed25519.Sign(c.key, []byte(timestamp+body))
+		req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(sig))
+	} else {
+		req.Header.Set("X-Signature-Ed25519", strings.Repeat("00", ed25519.SignatureSize))
+	}
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (c *interactionClient) command(body string) interactionResponse {
+	c.t.Helper()
+	rec := c.send(body, true)
+	if rec.Code != http.StatusOK {
+		c.t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var resp interactionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		c.t.Fatalf("invalid response: %v", err)
+	}
+	return resp
+}
+
+// deferred はコマンドが「考え中」で応答されることを確認し、その後に送られたリクエストを返す。
+func (c *interactionClient) deferred(body string) []webhookRequest {
+	c.t.Helper()
+	if resp := c.command(body); resp.Type != responseTypeDeferredChannelMessage {
+		c.t.Fatalf("type = %d, want %d", resp.Type, responseTypeDeferredChannelMessage)
+	}
+	c.handler.running.Wait()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+func TestInteractionHandler_Ping(t *testing.T) {
+	c := newInteractionClient(t, &fakeCommands{})
+	if resp := c.command(`{"type":1}`); resp.Type != responseTypePong {
+		t.Errorf("type = %d, want %d", resp.Type, responseTypePong)
+	}
+}
+
+func TestInteractionHandler_InvalidSignature(t *testing.T) {
+	c := newInteractionClient(t, &fakeCommands{})
+	if rec := c.send(`{"type":1}`, false); rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestInteractionHandler_StaleTimestamp(t *testing.T) {
+	c := newInteractionClient(t, &fakeCommands{})
+	for _, at := range []time.Time{time.Now().Add(-10 * time.Minute), time.Now().Add(10 * time.Minute)} {
+		if rec := c.sendAt(`{"type":1}`, true, at); rec.Code != http.StatusUnauthorized {
+			t.Errorf("timestamp %v: status = %d, want 401", at, rec.Code)
+		}
+	}
+}
+
+func TestInteractionHandler_Commands(t *testing.T) {
+	tests := []struct {
+		body  string
+		title string
+	}{
+		{body: `{"type":2,"application_id":"app","token":"tok","data":{"name":"tasks","options":[{"name":"today","type":1}]}}`, title: "today"},
+		{body: `{"type":2,"application_id":"app","token":"tok","data":{"name":"tasks","options":[{"name":"week","type":1}]}}`, title: "week"},
+		{body: `{"type":2,"application_id":"app","token":"tok","data":{"name":"reading"}}`, title: "reading"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			c := newInteractionClient(t, &fakeCommands{})
+			requests := c.deferred(tt.body)
+			if len(requests) != 1 {
+				t.Fatalf("requests = %+v, want 1", requests)
+			}
+			req := requests[0]
+			if req.method != http.MethodPatch || req.path != "/webhooks/app/tok/messages/@original" {
+				t.Errorf("request = %s %s, want PATCH /webhooks/app/tok/messages/@original", req.method, req.path)
+			}
+			if req.data.Content != tt.title {
+				t.Errorf("content = %q, want %q", req.data.Content, tt.title)
+			}
+			if len(req.data.Embeds) != 1 || req.data.Embeds[0].Description != "- [Work] 資料作成" {
+				t.Errorf("embeds = %+v", req.data.Embeds)
+			}
+		})
+	}
+}
+
+func TestInteractionHandler_Snooze(t *testing.T) {
+	commands := &fakeCommands{}
+	c := newInteractionClient(t, commands)
+
+	requests := c.deferred(`{"type":2,"application_id":"app","token":"tok","data":{"name":"snooze","options":[{"name":"task","type":3,"value":"資料"},{"name":"days","type":4,"value":3}]}}`)
+	if len(requests) != 1 || requests[0].data.Content != "snoozed" {
+		t.Fatalf("unexpected requests: %+v", requests)
+	}
+	if commands.snoozeQuery != "資料" || commands.snoozeDays != 3 {
+		t.Errorf("Snooze(%q, %d), want (資料, 3)", commands.snoozeQuery, commands.snoozeDays)
+	}
+}
+
+func TestInteractionHandler_InvalidOption(t *testing.T) {
+	commands := &fakeCommands{}
+	c := newInteractionClient(t, commands)
+
+	resp := c.command(`{"type":2,"data":{"name":"snooze","options":[{"name":"task","type":3,"value":"資料"},{"name":"days","type":4,"value":"three"}]}}`)
+	if resp.Type != responseTypeChannelMessage || resp.Data == nil || resp.Data.Flags != messageFlagEphemeral {
+		t.Fatalf("expected ephemeral error message, got %+v", resp)
+	}
+	if commands.snoozeQuery != "" {
+		t.Errorf("Snooze should not be called, got query %q", commands.snoozeQuery)
+	}
+}
+
+func TestInteractionHandler_CommandError(t *testing.T) {
+	c := newInteractionClient(t, &fakeCommands{err: errors.New("notion is down")})
+
+	requests := c.deferred(`{"type":2,"application_id":"app","token":"tok","data":{"name":"reading"}}`)
+	if len(requests) != 2 {
+		t.Fatalf("requests = %+v, want delete and follow-up", requests)
+	}
+	if requests[0].method != http.MethodDelete || requests[0].path != "/webhooks/app/tok/messages/@original" {
+		t.Errorf("first request = %s %s, want DELETE @original", requests[0].method, requests[0].path)
+	}
+	followup := requests[1]
+	if followup.method != http.MethodPost || followup.path != "/webhooks/app/tok" {
+		t.Errorf("second request = %s %s, want POST /webhooks/app/tok", followup.method, followup.path)
+	}
+	if followup.data.Flags != messageFlagEphemeral || followup.data.Content == "" {
+		t.Errorf("expected ephemeral error message, got %+v", followup.data)
+	}
+	if strings.Contains(followup.data.Content, "notion is down") {
+		t.Errorf("internal error should not be shown: %q", followup.data.Content)
+	}
+}
+
+// blockingCommands は release が閉じられるまで /tasks today の結果を返さない。
+type blockingCommands struct {
+	fakeCommands
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingCommands) TasksToday(ctx context.Context) (*notification.Message, error) {
+	close(b.started)
+	<-b.release
+	return b.message("today")
+}
+
+func TestInteractionHandler_ShutdownWaitsForCommands(t *testing.T) {
+	commands := &blockingCommands{started: make(chan struct{}), release: make(chan struct{})}
+	c := newInteractionClient(t, commands)
+	if resp := c.command(`{"type":2,"application_id":"app","token":"tok","data":{"name":"tasks","options":[{"name":"today","type":1}]}}`); resp.Type != responseTypeDeferredChannelMessage {
+		t.Fatalf("type = %d, want %d", resp.Type, responseTypeDeferredChannelMessage)
+	}
+	<-commands.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.handler.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want deadline exceeded while the command is running", err)
+	}
+
+	close(commands.release)
+	if err := c.handler.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) != 1 || c.requests[0].method != http.MethodPatch || c.requests[0].data.Content != "today" {
+		t.Errorf("expected the result to be sent before Shutdown returns, got %+v", c.requests)
+	}
+}
+
+func TestInteractionHandler_CommandDuringShutdown(t *testing.T) {
+	commands := &blockingCommands{started: make(chan struct{}), release: make(chan struct{})}
+	c := newInteractionClient(t, commands)
+	today := `{"type":2,"application_id":"app","token":"tok","data":{"name":"tasks","options":[{"name":"today","type":1}]}}`
+	if resp := c.command(today); resp.Type != responseTypeDeferredChannelMessage {
+		t.Fatalf("type = %d, want %d", resp.Type, responseTypeDeferredChannelMessage)
+	}
+	<-commands.started
+
+	done := make(chan error, 1)
+	go func() { done <- c.handler.Shutdown(context.Background()) }()
+	for {
+		c.handler.mu.Lock()
+		closed := c.handler.closed
+		c.handler.mu.Unlock()
+		if closed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Shutdown の待機中に届いたコマンドは実行せずに停止中と返す
+	resp := c.command(`{"type":2,"application_id":"app","token":"tok2","data":{"name":"reading"}}`)
+	if resp.Type != responseTypeChannelMessage || resp.Data == nil || resp.Data.Flags != messageFlagEphemeral {
+		t.Errorf("expected an ephemeral reply during shutdown, got %+v", resp)
+	}
+
+	close(commands.release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) != 1 || c.requests[0].data.Content != "today" {
+		t.Errorf("expected only the command accepted before shutdown to reply, got %+v", c.requests)
+	}
+}
+
+func TestNewInteractionHandler_InvalidKey(t *testing.T) {
+	if _, err := NewInteractionHandler("not-hex", &fakeCommands{}); err == nil {
+		t.Error("expected error for invalid public key")
+	}
+}
@@ -0,0 +1,102 @@
+package discord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord API の上限（文字数はコードポイント単位で数えられる）
+const (
+	maxContentLength     = 2000
+	maxDescriptionLength = 4096
+	maxEmbedsPerMessage  = 10
+	maxEmbedTotalLength  = 6000
+)
+
+// splitLines は各チャンクが limit 文字以内になるよう、行単位で text を分割する。
+// 1 行だけで limit を超える場合はその行を文字単位で切る。
+func splitLines(text string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+
+	flush := func() {
+		if currentLen > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		for _, part := range splitRunes(line, limit) {
+			partLen := utf8.RuneCountInString(part)
+			sep := 0
+			if currentLen > 0 {
+				sep = 1
+			}
+			if currentLen+sep+partLen > limit {
+				flush()
+				sep = 0
+			}
+			if sep > 0 {
+				current.WriteString("\n")
+			}
+			current.WriteString(part)
+			currentLen += sep + partLen
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+func splitRunes(s string, limit int) []string {
+	if utf8.RuneCountInString(s) <= limit {
+		return []string{s}
+	}
+	var parts []string
+	runes := []rune(s)
+	for len(runes) > limit {
+		parts = append(parts, string(runes[:limit]))
+		runes = runes[limit:]
+	}
+	return append(parts, string(runes))
+}
+
+// splitEmbeds は長い description を複数の embed に分け、
+// 1 メッセージあたりの embed 数と合計文字数の上限に収まるよう投稿単位にまとめる。
+func splitEmbeds(embeds []embed) [][]embed {
+	var split []embed
+	for _, e := range embeds {
+		descs := splitLines(e.Description, maxDescriptionLength)
+		if len(descs) == 0 {
+			descs = []string{""}
+		}
+		for i, desc := range descs {
+			part := embed{Description: desc, Color: e.Color}
+			if i == 0 {
+				part.Title = e.Title
+			}
+			split = append(split, part)
+		}
+	}
+
+	var batches [][]embed
+	var current []embed
+	currentLen := 0
+	for _, e := range split {
+		n := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+		if len(current) > 0 && (len(current) >= maxEmbedsPerMessage || currentLen+n > maxEmbedTotalLength) {
+			batches = append(batches, current)
+			current = nil
+			currentLen = 0
+		}
+		current = append(current, e)
+		currentLen += n
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
@@ -6,46 +6,203 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
 )
 
+// 分割投稿の間隔。Webhook のレート制限（おおよそ 2 秒に 5 回）に収まる値にしている。
+const defaultPostInterval = 500 * time.Millisecond
+
 type WebhookClient struct {
-	httpClient *http.Client
-	webhookURL string
+	httpClient   *http.Client
+	webhookURL   string
+	postInterval time.Duration
+	retry        httpretry.Policy
+}
+
+type Option func(*WebhookClient)
+
+// 429 などを受けたときの再試行方針を指定する。
+// Webhook への投稿は冪等ではないため、未処理が明らかな場合のみ再送される。
+func WithRetryPolicy(p httpretry.Policy) Option {
+	return func(c *WebhookClient) {
+		c.retry = p
+	}
 }
 
-func NewWebhookClient(webhookURL string) *WebhookClient {
-	return &WebhookClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-		webhookURL: webhookURL,
+func NewWebhookClient(webhookURL string, opts ...Option) *WebhookClient {
+	c := &WebhookClient{
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		webhookURL:   webhookURL,
+		postInterval: defaultPostInterval,
+		retry:        httpretry.DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-func (c *WebhookClient) Notify(ctx context.Context, message string) error {
-	payload := map[string]string{
-		"content": message,
+// Notify は Message のタイトルを本文に、各セクションを埋め込み（embed）として送信する。
+// Discord の上限を超える場合は複数の投稿に分け、本文に "(1/3)" 形式の番号を付ける。
+// Mention は最初の投稿の本文の先頭に付ける。
+func (c *WebhookClient) Notify(ctx context.Context, message *notification.Message) error {
+	batches := splitEmbeds(buildEmbeds(message.Sections))
+	if len(batches) == 0 {
+		batches = [][]embed{nil}
 	}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+	payloads := make([]webhookPayload, 0, len(batches))
+	for i, batch := range batches {
+		content := message.Title
+		if header := continuationHeader(i, len(batches)); header != "" {
+			content = header + " " + content
+		}
+		// メンションは最初の投稿にだけ付け、続きの投稿で何度も通知されないようにする
+		if i == 0 && message.Mention != "" {
+			content = message.Mention + " " + content
+		}
+		payloads = append(payloads, webhookPayload{
+			Content: content,
+			Embeds:  batch,
+		})
+	}
+	return c.postAll(ctx, payloads)
+}
+
+// NotifyText はテキストのみを送信する。2000 文字を超える場合は行単位で分割して順に投稿する。
+func (c *WebhookClient) NotifyText(ctx context.Context, message string) error {
+	// 番号ヘッダー "(99/99)\n" の分を空けておく
+	chunks := splitLines(message, maxContentLength-8)
+	if len(chunks) == 0 {
+		chunks = []string{message}
+	}
+
+	payloads := make([]webhookPayload, 0, len(chunks))
+	for i, chunk := range chunks {
+		header := continuationHeader(i, len(chunks))
+		if header != "" {
+			header += "\n"
+		}
+		payloads = append(payloads, webhookPayload{Content: header + chunk})
+	}
+	return c.postAll(ctx, payloads)
+}
+
+// 分割されていない場合は空文字を返す。
+func continuationHeader(i, total int) string {
+	if total <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("(%d/%d)", i+1, total)
+}
+
+// postAll は payloads を順番に投稿する。途中で失敗した場合は残りを送らずに返す。
+func (c *WebhookClient) postAll(ctx context.Context, payloads []webhookPayload) error {
+	for i, payload := range payloads {
+		if i > 0 {
+			if err := sleepContext(ctx, c.postInterval); err != nil {
+				return err
+			}
+		}
+		wait, err := c.post(ctx, payload)
+		if err != nil {
+			if len(payloads) > 1 {
+				return fmt.Errorf("failed to send part %d/%d: %w", i+1, len(payloads), err)
+			}
+			return err
+		}
+		if i < len(payloads)-1 && wait > 0 {
+			if err := sleepContext(ctx, wait); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+// post は 1 件投稿し、レート制限の残りが 0 の場合は次の投稿まで待つべき時間を返す。
+func (c *WebhookClient) post(ctx context.Context, payload webhookPayload) (time.Duration, error) {
+	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to marshal payload: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retry.Do(ctx, false, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		return c.httpClient.Do(req)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to send webhook: %w", err)
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook responded with status: %d", resp.StatusCode)
+		return 0, fmt.Errorf("webhook responded with status: %d", resp.StatusCode)
 	}
 
-	return nil
+	return rateLimitWait(resp.Header), nil
+}
+
+// X-RateLimit-Remaining が 0 のとき X-RateLimit-Reset-After（秒）だけ待つ。
+func rateLimitWait(h http.Header) time.Duration {
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return 0
+	}
+	secs, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return time.Duration(secs * float64(time.Second))
+}
+
+type webhookPayload struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []embed `json:"embeds,omitempty"`
+}
+
+type embed struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Color       int    `json:"color,omitempty"`
+}
+
+// 各項目はタスク名を Notion ページへのリンクにして 1 行ずつ並べる。
+func buildEmbeds(sections []notification.Section) []embed {
+	embeds := make([]embed, 0, len(sections))
+	for _, sec := range sections {
+		lines := make([]string, 0, len(sec.Items))
+		for _, item := range sec.Items {
+			lines = append(lines, item.Markdown())
+		}
+		embeds = append(embeds, embed{
+			Title:       sec.Title,
+			Description: strings.Join(lines, "\n"),
+			Color:       int(sec.Color),
+		})
+	}
+	return embeds
 }
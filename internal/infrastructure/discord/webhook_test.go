@@ -3,13 +3,19 @@ package discord
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
 )
 
-func TestWebhookClient_Notify(t *testing.T) {
+func TestWebhookClient_NotifyText(t *testing.T) {
 	var receivedPayload map[string]string
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -29,7 +35,7 @@ func TestWebhookClient_Notify(t *testing.T) {
 		webhookURL: server.URL,
 	}
 
-	err := client.Notify(context.Background(), "Test notification message")
+	err := client.NotifyText(context.Background(), "Test notification message")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -39,6 +45,105 @@ func TestWebhookClient_Notify(t *testing.T) {
 	}
 }
 
+func TestWebhookClient_Notify(t *testing.T) {
+	var receivedPayload webhookPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &WebhookClient{
+		httpClient: server.Client(),
+		webhookURL: server.URL,
+	}
+
+	message := &notification.Message{
+		Title: "📋 **締切が近いタスク一覧**",
+		Sections: []notification.Section{
+			{
+				Title: "本日締切",
+				Color: notification.ColorRed,
+				Items: []notification.Item{
+					{Name: "Task 1", URL: "https://www.notion.so/task-1", Project: "Work", Detail: "🔴 **本日締切**"},
+				},
+			},
+			{
+				Title: "明日締切",
+				Color: notification.ColorOrange,
+				Items: []notification.Item{
+					{Name: "Task 2", Detail: "🟠 明日締切"},
+				},
+			},
+		},
+	}
+
+	if err := client.Notify(context.Background(), message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedPayload.Content != message.Title {
+		t.Errorf("expected content %q, got %q", message.Title, receivedPayload.Content)
+	}
+	if len(receivedPayload.Embeds) != 2 {
+		t.Fatalf("expected 2 embeds, got %d", len(receivedPayload.Embeds))
+	}
+
+	first := receivedPayload.Embeds[0]
+	if first.Color != int(notification.ColorRed) {
+		t.Errorf("expected red embed, got %#x", first.Color)
+	}
+	if !strings.Contains(first.Description, "[Task 1](https://www.notion.so/task-1)") {
+		t.Errorf("expected task name to link to Notion page, got %q", first.Description)
+	}
+	if receivedPayload.Embeds[1].Color != int(notification.ColorOrange) {
+		t.Errorf("expected orange embed, got %#x", receivedPayload.Embeds[1].Color)
+	}
+	if receivedPayload.Embeds[1].Description != "- Task 2: 🟠 明日締切" {
+		t.Errorf("unexpected description: %q", receivedPayload.Embeds[1].Description)
+	}
+}
+
+func TestWebhookClient_Notify_MentionOnlyOnFirstPost(t *testing.T) {
+	var contents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		contents = append(contents, payload.Content)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(server.URL)
+	client.httpClient = server.Client()
+	client.postInterval = 0
+
+	// 11 セクションは 1 投稿あたりの埋め込み上限 (10) を超えるので 2 投稿に分かれる
+	message := &notification.Message{Mention: "@here", Title: "📋 **締切が近いタスク一覧**"}
+	for i := range 11 {
+		message.Sections = append(message.Sections, notification.Section{
+			Items: []notification.Item{{Name: fmt.Sprintf("Task %d", i)}},
+		})
+	}
+
+	if err := client.Notify(context.Background(), message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(contents) != 2 {
+		t.Fatalf("expected 2 posts, got %d", len(contents))
+	}
+	if contents[0] != "@here (1/2) 📋 **締切が近いタスク一覧**" {
+		t.Errorf("unexpected first content: %q", contents[0])
+	}
+	if strings.Contains(contents[1], "@here") {
+		t.Errorf("mention should not be repeated, got %q", contents[1])
+	}
+}
+
 func TestWebhookClient_Notify_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -50,8 +155,154 @@ func TestWebhookClient_Notify_Error(t *testing.T) {
 		webhookURL: server.URL,
 	}
 
-	err := client.Notify(context.Background(), "Test message")
+	err := client.NotifyText(context.Background(), "Test message")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestWebhookClient_NotifyText_SplitsLongMessage(t *testing.T) {
+	var received []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		received = append(received, payload.Content)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &WebhookClient{
+		httpClient: server.Client(),
+		webhookURL: server.URL,
+	}
+
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("- [Work] とても長いタスク名のタスク %03d: 🟡 あと3日", i))
+	}
+	message := strings.Join(lines, "\n")
+
+	if err := client.NotifyText(context.Background(), message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received) < 2 {
+		t.Fatalf("expected message to be split, got %d post(s)", len(received))
+	}
+
+	var rebuilt []string
+	for i, content := range received {
+		if n := utf8.RuneCountInString(content); n > maxContentLength {
+			t.Errorf("post %d has %d characters, exceeds %d", i+1, n, maxContentLength)
+		}
+		header := fmt.Sprintf("(%d/%d)\n", i+1, len(received))
+		if !strings.HasPrefix(content, header) {
+			t.Errorf("post %d: expected header %q, got %q", i+1, header, content[:20])
+		}
+		rebuilt = append(rebuilt, strings.TrimPrefix(content, header))
+	}
+
+	if strings.Join(rebuilt, "\n") != message {
+		t.Error("chunks do not reassemble into the original message in order")
+	}
+}
+
+func TestWebhookClient_Notify_SplitsEmbeds(t *testing.T) {
+	var received []webhookPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		received = append(received, payload)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &WebhookClient{
+		httpClient: server.Client(),
+		webhookURL: server.URL,
+	}
+
+	section := notification.Section{Title: "近日締切", Color: notification.ColorYellow}
+	for i := 0; i < 300; i++ {
+		section.Items = append(section.Items, notification.Item{
+			Name:   fmt.Sprintf("Task %03d", i),
+			URL:    fmt.Sprintf("https://www.notion.so/task-%03d", i),
+			Detail: "🟡 あと3日",
+		})
+	}
+	message := &notification.Message{Title: "📋 **締切が近いタスク一覧**", Sections: []notification.Section{section}}
+
+	if err := client.Notify(context.Background(), message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received) < 2 {
+		t.Fatalf("expected embeds to be split across posts, got %d post(s)", len(received))
+	}
+
+	next := 0
+	for i, payload := range received {
+		if want := fmt.Sprintf("(%d/%d) ", i+1, len(received)); !strings.HasPrefix(payload.Content, want) {
+			t.Errorf("post %d: expected content to start with %q, got %q", i+1, want, payload.Content)
+		}
+		total := 0
+		for _, e := range payload.Embeds {
+			if utf8.RuneCountInString(e.Description) > maxDescriptionLength {
+				t.Errorf("post %d: embed description exceeds %d characters", i+1, maxDescriptionLength)
+			}
+			total += utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+			for _, line := range strings.Split(e.Description, "\n") {
+				if want := fmt.Sprintf("Task %03d", next); !strings.Contains(line, want) {
+					t.Fatalf("expected %q next, got %q", want, line)
+				}
+				next++
+			}
+		}
+		if total > maxEmbedTotalLength {
+			t.Errorf("post %d: embeds total %d characters, exceeds %d", i+1, total, maxEmbedTotalLength)
+		}
+	}
+	if next != 300 {
+		t.Errorf("expected 300 items, got %d", next)
+	}
+}
+
+func TestWebhookClient_Notify_StopsOnError(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := &WebhookClient{
+		httpClient: server.Client(),
+		webhookURL: server.URL,
+	}
+
+	message := strings.Repeat(strings.Repeat("x", 100)+"\n", 50)
+	if err := client.NotifyText(context.Background(), message); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if posts != 1 {
+		t.Errorf("expected remaining chunks not to be sent, got %d posts", posts)
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("X-RateLimit-Reset-After", "1.5")
+	if got := rateLimitWait(h); got != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s, got %v", got)
+	}
+
+	h.Set("X-RateLimit-Remaining", "3")
+	if got := rateLimitWait(h); got != 0 {
+		t.Errorf("expected no wait, got %v", got)
+	}
+}
@@ -0,0 +1,130 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+// buildMessage は件名・宛先ヘッダーと multipart/alternative（text/plain, text/html）の本文を組み立てる。
+func buildMessage(from string, to []string, message *notification.Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	header := []string{
+		"From: " + from,
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + mime.QEncoding.Encode("UTF-8", plain(message.Title)),
+		"Date: " + now.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", mw.Boundary()),
+	}
+	var out bytes.Buffer
+	out.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	html, err := renderHTML(message)
+	if err != nil {
+		return nil, err
+	}
+	// 受信側は後のパートを優先して表示するため、HTML を後に置く
+	if err := writePart(mw, "text/plain", textBody(message)); err != nil {
+		return nil, err
+	}
+	if err := writePart(mw, "text/html", html); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+func writePart(mw *multipart.Writer, contentType, body string) error {
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qw := quotedprintable.NewWriter(pw)
+	if _, err := qw.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+// textBody は Message.Text からメンションを除いたもの。
+func textBody(message *notification.Message) string {
+	m := *message
+	m.Mention = ""
+	return m.Text()
+}
+
+var htmlTemplate = template.Must(template.New("email").Funcs(template.FuncMap{
+	"markdown": markdown,
+	"plain":    plain,
+	"hasActions": func(items []notification.Item) bool {
+		for _, item := range items {
+			if len(item.Actions) > 0 {
+				return true
+			}
+		}
+		return false
+	},
+	"color": func(c notification.Color) string {
+		if c == notification.ColorNone {
+			return "#CCCCCC"
+		}
+		return fmt.Sprintf("#%06X", int(c))
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>{{markdown .Title}}</h2>
+{{range .Sections}}<div style="border-left: 4px solid {{color .Color}}; padding-left: 8px; margin-bottom: 16px;">
+{{$actions := hasActions .Items}}{{if .Title}}<h3>{{.Title}}</h3>
+{{end}}<table style="border-collapse: collapse;">
+<tr><th align="left">プロジェクト</th><th align="left">タスク</th><th align="left">詳細</th>{{if $actions}}<th align="left">操作</th>{{end}}</tr>
+{{range .Items}}<tr><td>{{.Project}}</td><td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{markdown .Detail}}</td>{{if $actions}}<td>{{range $i, $a := .Actions}}{{if $i}} · {{end}}<a href="{{$a.URL}}">{{$a.Label}}</a>{{end}}</td>{{end}}</tr>
+{{end}}</table>
+</div>
+{{end}}</body>
+</html>
+`))
+
+// renderHTML はセクションごとに Discord の埋め込みと同じ色の枠線を付けた表を描画する。
+func renderHTML(message *notification.Message) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, message); err != nil {
+		return "", fmt.Errorf("failed to render html: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// markdown は Message 内の **太字** を <strong> に変換し、それ以外はエスケープする。
+func markdown(s string) template.HTML {
+	var sb strings.Builder
+	for i, part := range strings.Split(s, "**") {
+		escaped := template.HTMLEscapeString(part)
+		if i%2 == 1 {
+			escaped = "<strong>" + escaped + "</strong>"
+		}
+		sb.WriteString(escaped)
+	}
+	return template.HTML(sb.String())
+}
+
+func plain(s string) string {
+	return strings.ReplaceAll(s, "**", "")
+}
@@ -0,0 +1,146 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// SMTPClient は通知を text/plain と text/html のマルチパートメールとして SMTP で送信する。
+type SMTPClient struct {
+	addr      string
+	host      string
+	from      string
+	to        []string
+	username  string
+	password  string
+	startTLS  bool
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+type Option func(*SMTPClient)
+
+// PLAIN 認証を使う。暗号化されていない接続では localhost 以外への認証を拒否するため、通常は WithStartTLS と組み合わせる。
+func WithAuth(username, password string) Option {
+	return func(c *SMTPClient) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// STARTTLS で接続を暗号化する。サーバーが対応していない場合は送信しない。
+// tlsConfig が nil の場合はホスト名で証明書を検証する。
+func WithStartTLS(tlsConfig *tls.Config) Option {
+	return func(c *SMTPClient) {
+		c.startTLS = true
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// 接続から送信完了までのタイムアウトを指定する。省略時は 30 秒。
+func WithTimeout(d time.Duration) Option {
+	return func(c *SMTPClient) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// addr は "host:port" 形式。
+func NewSMTPClient(addr, from string, to []string, opts ...Option) (*SMTPClient, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address %q: %w", addr, err)
+	}
+	c := &SMTPClient{
+		addr:    addr,
+		host:    host,
+		from:    from,
+		to:      to,
+		timeout: defaultTimeout,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Notify はタイトルを件名、各セクションを表にしたメールを全宛先に 1 通で送信する。
+// Mention はメールでは意味を持たないため付けない。
+func (c *SMTPClient) Notify(ctx context.Context, message *notification.Message) error {
+	body, err := buildMessage(c.from, c.to, message, c.now())
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+	return c.send(ctx, body)
+}
+
+func (c *SMTPClient) send(ctx context.Context, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if c.startTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server does not support STARTTLS")
+		}
+		tlsConfig := c.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: c.host}
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(c.from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range c.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected email: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,303 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+// fakeSMTPServer は 1 接続ずつ処理するテスト用の SMTP サーバー。
+// tlsConfig を指定すると STARTTLS を提供する。
+type fakeSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu       sync.Mutex
+	from     string
+	rcpts    []string
+	data     string
+	authUser string
+	usedTLS  bool
+}
+
+func newFakeSMTPServer(t *testing.T, tlsConfig *tls.Config) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: l, tlsConfig: tlsConfig}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250-fake")
+			if s.tlsConfig != nil && !s.isTLS() {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			s.mu.Lock()
+			s.usedTLS = true
+			s.mu.Unlock()
+		case "AUTH":
+			// AUTH PLAIN <base64("\x00user\x00pass")>
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			parts := strings.Split(string(decoded), "\x00")
+			if len(parts) != 3 || parts[2] != "secret" {
+				reply("535 authentication failed")
+				continue
+			}
+			s.mu.Lock()
+			s.authUser = parts[1]
+			s.mu.Unlock()
+			reply("235 ok")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = line
+			s.mu.Unlock()
+			reply("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, line)
+			s.mu.Unlock()
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.mu.Lock()
+			s.data = sb.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) isTLS() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usedTLS
+}
+
+// testTLSConfigs は httptest の自己署名証明書を使ったサーバー側・クライアント側の TLS 設定を返す。
+func testTLSConfigs(t *testing.T) (server, client *tls.Config) {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(ts.Close)
+	roots := ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	return &tls.Config{Certificates: ts.TLS.Certificates}, &tls.Config{RootCAs: roots, ServerName: "127.0.0.1"}
+}
+
+func testMessage() *notification.Message {
+	return &notification.Message{
+		Mention: "@here",
+		Title:   "📋 **締切が近いタスク一覧**",
+		Sections: []notification.Section{{
+			Title: "本日締切",
+			Color: notification.ColorRed,
+			Items: []notification.Item{
+				{Name: "資料作成 <v2>", URL: "https://notion.so/1", Project: "Work", Detail: "🔴 **本日締切**"},
+			},
+		}},
+	}
+}
+
+// parseParts は受信したメールを text/plain と text/html の本文に分ける。
+func parseParts(t *testing.T, data string) (*mail.Message, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse email: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected Content-Type %q: %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	parts := make(map[string]string)
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(p))
+		if err != nil {
+			t.Fatalf("failed to decode part: %v", err)
+		}
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+	return msg, parts
+}
+
+func TestSMTPClient_Notify(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	client, err := NewSMTPClient(server.addr(), "notifier@example.com", []string{"a@example.com", "b@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.now = func() time.Time { return time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) }
+
+	if err := client.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.from != "MAIL FROM:<notifier@example.com>" {
+		t.Errorf("from = %q", server.from)
+	}
+	if len(server.rcpts) != 2 || server.rcpts[1] != "RCPT TO:<b@example.com>" {
+		t.Errorf("rcpts = %v", server.rcpts)
+	}
+
+	msg, parts := parseParts(t, server.data)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "📋 締切が近いタスク一覧" {
+		t.Errorf("subject = %q, %v", subject, err)
+	}
+	if msg.Header.Get("To") != "a@example.com, b@example.com" {
+		t.Errorf("to = %q", msg.Header.Get("To"))
+	}
+
+	text := parts["text/plain"]
+	if strings.Contains(text, "@here") || !strings.Contains(text, "- [Work] 資料作成 <v2>: 🔴 **本日締切**") {
+		t.Errorf("unexpected text part: %s", text)
+	}
+	html := parts["text/html"]
+	for _, want := range []string{
+		"<h2>📋 <strong>締切が近いタスク一覧</strong></h2>",
+		"border-left: 4px solid #E74C3C",
+		`<td>Work</td><td><a href="https://notion.so/1">資料作成 &lt;v2&gt;</a></td><td>🔴 <strong>本日締切</strong></td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected html to contain %q, got: %s", want, html)
+		}
+	}
+}
+
+func TestSMTPClient_Notify_StartTLSAndAuth(t *testing.T) {
+	serverTLS, clientTLS := testTLSConfigs(t)
+	server := newFakeSMTPServer(t, serverTLS)
+	client, err := NewSMTPClient(server.addr(), "notifier@example.com", []string{"a@example.com"},
+		WithStartTLS(clientTLS),
+		WithAuth("user", "secret"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := client.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if !server.usedTLS {
+		t.Error("expected STARTTLS to be used")
+	}
+	if server.authUser != "user" {
+		t.Errorf("auth user = %q, want user", server.authUser)
+	}
+	if server.data == "" {
+		t.Error("expected email to be sent")
+	}
+}
+
+func TestSMTPClient_Notify_StartTLSUnsupported(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	client, err := NewSMTPClient(server.addr(), "notifier@example.com", []string{"a@example.com"}, WithStartTLS(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = client.Notify(context.Background(), testMessage())
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("expected STARTTLS error, got %v", err)
+	}
+}
+
+func TestSMTPClient_Notify_AuthFailure(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	client, err := NewSMTPClient(server.addr(), "notifier@example.com", []string{"a@example.com"}, WithAuth("user", "wrong"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := client.Notify(context.Background(), testMessage()); err == nil {
+		t.Error("expected auth error")
+	}
+}
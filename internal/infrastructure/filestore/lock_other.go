@@ -0,0 +1,9 @@
+//go:build !unix
+
+package filestore
+
+// flock が使えない環境ではプロセス間のロックを取らない。
+// 同じ state_file を使うプロセスは 1 つだけにすること。
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,131 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+// SnoozeStore は通知を止めるルールを JSON ファイルに保存する notification.SnoozeStore の実装。
+// CLI（snooze コマンド）と serve が同じファイルを使うため、操作のたびにファイルロックを取ってから読み直す。
+type SnoozeStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+type snoozeEntry struct {
+	Scope notification.SnoozeScope `json:"scope"`
+	Key   string                   `json:"key"`
+	Label string                   `json:"label,omitempty"`
+	// 省略時は解除するまで止める
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// NewSnoozeStore は path のファイルを読み込めることを確認する。ファイルが存在しない場合は空の状態から始める。
+func NewSnoozeStore(path string) (*SnoozeStore, error) {
+	s := &SnoozeStore{path: path}
+	err := s.withLock(func() error {
+		_, err := s.load()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Snooze は now の時点で期限切れのルールを削除してから保存する。
+func (s *SnoozeStore) Snooze(ctx context.Context, snooze notification.Snooze, now time.Time) error {
+	return s.withLock(func() error {
+		entries, err := s.load()
+		if err != nil {
+			return err
+		}
+		entries = slices.DeleteFunc(entries, func(e notification.Snooze) bool {
+			return (e.Scope == snooze.Scope && e.Key == snooze.Key) || !e.Active(now)
+		})
+		return s.save(append(entries, snooze))
+	})
+}
+
+func (s *SnoozeStore) Unsnooze(ctx context.Context, scope notification.SnoozeScope, key string) (bool, error) {
+	var removed bool
+	err := s.withLock(func() error {
+		entries, err := s.load()
+		if err != nil {
+			return err
+		}
+		remaining := slices.DeleteFunc(slices.Clone(entries), func(e notification.Snooze) bool {
+			return e.Scope == scope && e.Key == key
+		})
+		if len(remaining) == len(entries) {
+			return nil
+		}
+		removed = true
+		return s.save(remaining)
+	})
+	return removed, err
+}
+
+func (s *SnoozeStore) Snoozes(ctx context.Context, now time.Time) ([]notification.Snooze, error) {
+	var entries []notification.Snooze
+	err := s.withLock(func() error {
+		var err error
+		entries, err = s.load()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(entries, func(e notification.Snooze) bool { return !e.Active(now) }), nil
+}
+
+func (s *SnoozeStore) withLock(fn func() error) error {
+	return withFileLock(&s.mu, s.path, fn)
+}
+
+// load はファイルのルールを保存した順に返す。
+func (s *SnoozeStore) load() ([]notification.Snooze, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snooze file: %w", err)
+	}
+
+	var entries []snoozeEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse snooze file: %w", err)
+	}
+
+	snoozes := make([]notification.Snooze, 0, len(entries))
+	for _, e := range entries {
+		sn := notification.Snooze{Scope: e.Scope, Key: e.Key, Label: e.Label}
+		if e.Until != nil {
+			sn.Until = *e.Until
+		}
+		snoozes = append(snoozes, sn)
+	}
+	return snoozes, nil
+}
+
+func (s *SnoozeStore) save(snoozes []notification.Snooze) error {
+	entries := make([]snoozeEntry, 0, len(snoozes))
+	for _, sn := range snoozes {
+		e := snoozeEntry{Scope: sn.Scope, Key: sn.Key, Label: sn.Label}
+		if !sn.Until.IsZero() {
+			until := sn.Until
+			e.Until = &until
+		}
+		entries = append(entries, e)
+	}
+	return writeJSON(s.path, entries)
+}
@@ -0,0 +1,135 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+func TestSnoozeStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snooze.json")
+	ctx := context.Background()
+	now := time.Now()
+	until := now.Add(24 * time.Hour).Truncate(time.Second)
+
+	store, err := NewSnoozeStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snoozes, _ := store.Snoozes(ctx, now); len(snoozes) != 0 {
+		t.Fatalf("expected no snooze in a new store, got %v", snoozes)
+	}
+	for _, sn := range []notification.Snooze{
+		{Scope: notification.SnoozeTask, Key: "task-1", Label: "資料作成", Until: now.Add(time.Hour)},
+		{Scope: notification.SnoozeTask, Key: "task-1", Label: "資料作成", Until: until},
+		{Scope: notification.SnoozeTask, Key: "task-2", Until: now.Add(-time.Hour)},
+		{Scope: notification.SnoozeProject, Key: "家事"},
+	} {
+		if err := store.Snooze(ctx, sn, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// 別のプロセス（CLI）からの変更も読み直す
+	other, err := NewSnoozeStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, err := other.Unsnooze(ctx, notification.SnoozeProject, "家事"); !ok || err != nil {
+		t.Fatalf("Unsnooze() = %v, %v", ok, err)
+	}
+	if err := other.Snooze(ctx, notification.Snooze{Scope: notification.SnoozeProject, Key: "Work"}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snoozes, err := store.Snoozes(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snoozes) != 2 {
+		t.Fatalf("expected 2 snoozes, got %+v", snoozes)
+	}
+	if sn := snoozes[0]; sn.Key != "task-1" || sn.Label != "資料作成" || !sn.Until.Equal(until) {
+		t.Errorf("snoozes[0] = %+v, want task-1 until %v", sn, until)
+	}
+	if sn := snoozes[1]; sn.Scope != notification.SnoozeProject || sn.Key != "Work" || !sn.Until.IsZero() {
+		t.Errorf("snoozes[1] = %+v, want project Work without expiry", sn)
+	}
+	if snoozes, _ := store.Snoozes(ctx, until); len(snoozes) != 1 {
+		t.Errorf("expected task snooze to expire at its deadline, got %+v", snoozes)
+	}
+}
+
+func TestSnoozeStore_PrunesAtGivenTime(t *testing.T) {
+	store, err := NewSnoozeStore(filepath.Join(t.TempDir(), "snooze.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	past := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 期限切れかどうかはシステム時計ではなく渡された now で判断する
+	if err := store.Snooze(ctx, notification.Snooze{Scope: notification.SnoozeTask, Key: "task-1", Until: past}, now.AddDate(-2, 0, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Snooze(ctx, notification.Snooze{Scope: notification.SnoozeProject, Key: "Work"}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snoozes, err := store.Snoozes(ctx, past.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snoozes) != 1 || snoozes[0].Key != "Work" {
+		t.Errorf("expected the rule expired at now to be pruned, got %+v", snoozes)
+	}
+}
+
+func TestSnoozeStore_ConcurrentWritersKeepAllRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snooze.json")
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	const perStore = 20
+
+	// serve と CLI が同じファイルに同時に書き込む状態を再現する
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*perStore)
+	for _, prefix := range []string{"serve", "cli"} {
+		store, err := NewSnoozeStore(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perStore {
+				sn := notification.Snooze{Scope: notification.SnoozeTask, Key: fmt.Sprintf("%s-%d", prefix, i)}
+				if err := store.Snooze(ctx, sn, now); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, err := NewSnoozeStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snoozes, err := store.Snoozes(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snoozes) != 2*perStore {
+		t.Errorf("expected %d snoozes, got %d", 2*perStore, len(snoozes))
+	}
+}
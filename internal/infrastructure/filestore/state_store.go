@@ -0,0 +1,143 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+// この期間より前に送信された記録は、完了したタスクのものとみなして保存時に削除する。
+const defaultRetention = 30 * 24 * time.Hour
+
+// StateStore は送信済み状態を JSON ファイルに保存する notification.StateStore の実装。
+// Pod を再起動しても状態が残るよう、永続ボリューム上のパスを指定する。
+// 複数のプロセスが同じファイルを使っても互いの記録を消さないよう、
+// 操作のたびにファイルロックを取ってから読み直す。
+type StateStore struct {
+	mu        sync.Mutex
+	path      string
+	retention time.Duration
+}
+
+// NewStateStore は path のファイルを読み込めることを確認する。ファイルが存在しない場合は空の状態から始める。
+func NewStateStore(path string) (*StateStore, error) {
+	s := &StateStore{
+		path:      path,
+		retention: defaultRetention,
+	}
+	err := s.withLock(func() error {
+		_, err := s.load()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *StateStore) LastSent(ctx context.Context, key notification.StateKey) (time.Time, bool, error) {
+	var (
+		sentAt time.Time
+		ok     bool
+	)
+	err := s.withLock(func() error {
+		records, err := s.load()
+		if err != nil {
+			return err
+		}
+		sentAt, ok = records[recordKey(key)]
+		return nil
+	})
+	return sentAt, ok, err
+}
+
+func (s *StateStore) MarkSent(ctx context.Context, keys []notification.StateKey, sentAt time.Time) error {
+	return s.withLock(func() error {
+		records, err := s.load()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			records[recordKey(key)] = sentAt
+		}
+		for k, t := range records {
+			if sentAt.Sub(t) > s.retention {
+				delete(records, k)
+			}
+		}
+		return writeJSON(s.path, records)
+	})
+}
+
+func (s *StateStore) withLock(fn func() error) error {
+	return withFileLock(&s.mu, s.path, fn)
+}
+
+func (s *StateStore) load() (map[string]time.Time, error) {
+	records := make(map[string]time.Time)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return records, nil
+}
+
+// withFileLock はプロセス内の mutex と、他のプロセスと共有するファイルロックを取ってから fn を実行する。
+// ファイルは置き換えで更新されるため、ロックには別ファイル（path + ".lock"）を使う。
+func withFileLock(mu *sync.Mutex, path string, fn func() error) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// 書き込み途中で落ちても壊れないよう、一時ファイルに書いてから置き換える。
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+func recordKey(key notification.StateKey) string {
+	return fmt.Sprintf("%s/%s/%s", key.TaskID, key.Kind, key.Bucket)
+}
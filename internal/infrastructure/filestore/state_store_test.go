@@ -0,0 +1,101 @@
+package filestore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+)
+
+func TestStateStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "sent.json")
+	ctx := context.Background()
+	sentAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	key := notification.StateKey{TaskID: "task-1", Kind: notification.KindDeadline, Bucket: "tomorrow"}
+
+	store, err := NewStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, _ := store.LastSent(ctx, key); ok {
+		t.Fatal("expected no record in a new store")
+	}
+	if err := store.MarkSent(ctx, []notification.StateKey{key}, sentAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened, err := NewStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, ok, err := reopened.LastSent(ctx, key)
+	if err != nil || !ok {
+		t.Fatalf("expected record after reopen, ok=%v err=%v", ok, err)
+	}
+	if !got.Equal(sentAt) {
+		t.Errorf("expected %v, got %v", sentAt, got)
+	}
+
+	other := notification.StateKey{TaskID: "task-1", Kind: notification.KindDeadline, Bucket: "today"}
+	if _, ok, _ := reopened.LastSent(ctx, other); ok {
+		t.Error("different bucket should not share the record")
+	}
+}
+
+func TestStateStore_PrunesOldRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sent.json")
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	oldKey := notification.StateKey{TaskID: "old", Kind: notification.KindReading}
+	newKey := notification.StateKey{TaskID: "new", Kind: notification.KindReading}
+
+	store, err := NewStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.MarkSent(ctx, []notification.StateKey{oldKey}, now.Add(-defaultRetention-time.Hour))
+	store.MarkSent(ctx, []notification.StateKey{newKey}, now)
+
+	if _, ok, _ := store.LastSent(ctx, oldKey); ok {
+		t.Error("expected old record to be pruned")
+	}
+	if _, ok, _ := store.LastSent(ctx, newKey); !ok {
+		t.Error("expected new record to be kept")
+	}
+}
+
+func TestStateStore_SharedFileKeepsOtherWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sent.json")
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	firstKey := notification.StateKey{TaskID: "task-1", Kind: notification.KindDeadline, Bucket: "today"}
+	secondKey := notification.StateKey{TaskID: "task-2", Kind: notification.KindReading, Bucket: "due-in-1d"}
+
+	// 2 つのプロセスが同じファイルを開いた状態を再現する
+	first, err := NewStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := NewStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := first.MarkSent(ctx, []notification.StateKey{firstKey}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, _ := second.LastSent(ctx, firstKey); !ok {
+		t.Error("expected record written by another store to be visible")
+	}
+	if err := second.MarkSent(ctx, []notification.StateKey{secondKey}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []notification.StateKey{firstKey, secondKey} {
+		if _, ok, _ := first.LastSent(ctx, key); !ok {
+			t.Errorf("expected record %v to be kept", key)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package httpretry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 1 * time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+)
+
+// Policy は HTTP リクエストの再試行方針。
+// 指数バックオフ（フルジッター）で待ち、Retry-After ヘッダーや Discord の retry_after があればそれに従う
+// （ただし MaxBackoff を超える場合は MaxBackoff だけ待つ）。
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// テスト用に差し替えられる
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(max time.Duration) time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// NoRetry は 1 回だけ送信するポリシー。
+func NoRetry() Policy {
+	return Policy{MaxAttempts: 1}
+}
+
+// Do は send が返したレスポンスを見て、必要なら再送する。
+// send は毎回新しい *http.Request を作って送信すること（リクエストボディは再利用できないため）。
+//
+// idempotent が false の場合（Discord への投稿など）は、サーバー側で処理されていないことが
+// 明らかなケース（429 と接続確立前のエラー）だけを再試行する。
+// 最終的なレスポンスのボディは呼び出し側で Close する。
+func (p Policy) Do(ctx context.Context, idempotent bool, send func() (*http.Response, error)) (*http.Response, error) {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+
+		if attempt >= attempts || !shouldRetry(resp, err, idempotent) {
+			return resp, err
+		}
+
+		wait := p.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				wait = p.capRetryAfter(d)
+			}
+			resp.Body.Close()
+		}
+
+		if err := p.sleepContext(ctx, wait); err != nil {
+			return nil, fmt.Errorf("retry aborted: %w", err)
+		}
+	}
+}
+
+func shouldRetry(resp *http.Response, err error, idempotent bool) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent || isDialError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// 接続確立前のエラーであれば、リクエストはサーバーに届いていない。
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// backoff は attempt 回目の失敗後に待つ時間を [0, min(MaxBackoff, InitialBackoff*2^(attempt-1))) から選ぶ。
+func (p Policy) backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+	d := p.InitialBackoff << (attempt - 1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+	if p.jitter != nil {
+		return p.jitter(d)
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// capRetryAfter はサーバーが指定した待ち時間を MaxBackoff までに抑える。
+// 誤った Retry-After で実行が何時間も止まらないようにするため。
+func (p Policy) capRetryAfter(d time.Duration) time.Duration {
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+func (p Policy) sleepContext(ctx context.Context, d time.Duration) error {
+	if p.sleep != nil {
+		return p.sleep(ctx, d)
+	}
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter は Retry-After ヘッダー（秒または HTTP 日付）か、
+// Discord が 429 のボディで返す retry_after（秒）から待ち時間を求める。
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(time.Until(t), 0), true
+		}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Body == nil {
+		return 0, false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return 0, false
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var rateLimit struct {
+		RetryAfter *float64 `json:"retry_after"`
+	}
+	if err := json.Unmarshal(body, &rateLimit); err != nil || rateLimit.RetryAfter == nil || *rateLimit.RetryAfter < 0 {
+		return 0, false
+	}
+	return time.Duration(*rateLimit.RetryAfter * float64(time.Second)), true
+}
@@ -0,0 +1,246 @@
+package httpretry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testPolicy は待ち時間を記録するだけで実際には待たないポリシーを返す。
+func testPolicy(maxAttempts int, waits *[]time.Duration) Policy {
+	p := DefaultPolicy()
+	p.MaxAttempts = maxAttempts
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	p.jitter = func(max time.Duration) time.Duration { return max }
+	return p
+}
+
+// statusSequence は呼ばれるたびに statuses を順に返すテストサーバーを立てる。
+func statusSequence(t *testing.T, statuses []int, setup func(w http.ResponseWriter, i int)) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := calls
+		calls++
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		if setup != nil {
+			setup(w, i)
+		}
+		w.WriteHeader(statuses[i])
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func get(server *httptest.Server) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return server.Client().Do(req)
+	}
+}
+
+func TestPolicy_Do_RetriesServerErrorsWhenIdempotent(t *testing.T) {
+	server, calls := statusSequence(t, []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, nil)
+
+	var waits []time.Duration
+	resp, err := testPolicy(3, &waits).Do(context.Background(), true, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 calls, got %d", *calls)
+	}
+	// 指数バックオフ: 1s, 2s
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("unexpected backoff: %v", waits)
+	}
+}
+
+func TestPolicy_Do_DoesNotRetryServerErrorsWhenNotIdempotent(t *testing.T) {
+	server, calls := statusSequence(t, []int{http.StatusBadGateway, http.StatusOK}, nil)
+
+	var waits []time.Duration
+	resp, err := testPolicy(3, &waits).Do(context.Background(), false, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected 502, got %d", resp.StatusCode)
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 call, got %d", *calls)
+	}
+}
+
+func TestPolicy_Do_DoesNotRetryClientErrors(t *testing.T) {
+	server, calls := statusSequence(t, []int{http.StatusBadRequest, http.StatusOK}, nil)
+
+	var waits []time.Duration
+	resp, err := testPolicy(3, &waits).Do(context.Background(), true, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if *calls != 1 {
+		t.Errorf("expected 1 call, got %d", *calls)
+	}
+}
+
+func TestPolicy_Do_HonoursRetryAfterHeader(t *testing.T) {
+	server, calls := statusSequence(t, []int{http.StatusTooManyRequests, http.StatusOK}, func(w http.ResponseWriter, i int) {
+		if i == 0 {
+			w.Header().Set("Retry-After", "7")
+		}
+	})
+
+	var waits []time.Duration
+	resp, err := testPolicy(3, &waits).Do(context.Background(), false, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if *calls != 2 {
+		t.Errorf("expected 2 calls, got %d", *calls)
+	}
+	if len(waits) != 1 || waits[0] != 7*time.Second {
+		t.Errorf("expected to wait 7s, got %v", waits)
+	}
+}
+
+func TestPolicy_Do_CapsRetryAfterAtMaxBackoff(t *testing.T) {
+	server, _ := statusSequence(t, []int{http.StatusTooManyRequests, http.StatusOK}, func(w http.ResponseWriter, i int) {
+		if i == 0 {
+			w.Header().Set("Retry-After", "7200")
+		}
+	})
+
+	var waits []time.Duration
+	resp, err := testPolicy(3, &waits).Do(context.Background(), false, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(waits) != 1 || waits[0] != DefaultMaxBackoff {
+		t.Errorf("expected to wait %v, got %v", DefaultMaxBackoff, waits)
+	}
+}
+
+func TestPolicy_Do_HonoursDiscordRetryAfterBody(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.25, "global": false}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	resp, err := testPolicy(3, &waits).Do(context.Background(), false, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", resp.StatusCode)
+	}
+	if len(waits) != 1 || waits[0] != 250*time.Millisecond {
+		t.Errorf("expected to wait 250ms, got %v", waits)
+	}
+}
+
+func TestPolicy_Do_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := statusSequence(t, []int{http.StatusServiceUnavailable}, nil)
+
+	var waits []time.Duration
+	resp, err := testPolicy(4, &waits).Do(context.Background(), true, get(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected last response 503, got %d", resp.StatusCode)
+	}
+	if *calls != 4 {
+		t.Errorf("expected 4 calls, got %d", *calls)
+	}
+}
+
+func TestPolicy_Do_RetriesDialErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	attempts := 0
+	send := func() (*http.Response, error) {
+		attempts++
+		return http.Get(url)
+	}
+
+	var waits []time.Duration
+	if _, err := testPolicy(3, &waits).Do(context.Background(), false, send); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts for dial error, got %d", attempts)
+	}
+}
+
+func TestPolicy_Do_StopsWhenContextCancelled(t *testing.T) {
+	server, calls := statusSequence(t, []int{http.StatusServiceUnavailable}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := DefaultPolicy()
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	if _, err := p.Do(ctx, true, get(server)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 call, got %d", *calls)
+	}
+}
+
+func TestPolicy_backoff_CapsAtMaxBackoff(t *testing.T) {
+	p := Policy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		jitter:         func(max time.Duration) time.Duration { return max },
+	}
+
+	want := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
@@ -6,145 +6,245 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
 	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
 )
 
 const (
 	notionAPIVersion = "2022-06-28"
 	notionBaseURL    = "https://api.notion.com/v1"
+
+	// Notion API の page_size 上限
+	maxPageSize     = 100
+	defaultMaxPages = 50
 )
 
 type Client struct {
 	httpClient *http.Client
+	baseURL    string
 	apiToken   string
 	databaseID string
+	pageSize   int
+	maxPages   int
+	props      PropertyMapping
+	// ステータス名からグループへの対応。Notion のステータスグループより優先される
+	statusOverrides statusMapping
+	// 優先度のプロパティ値と task.Priority の対応
+	priorities map[string]task.Priority
+	retry      httpretry.Policy
+	location   *time.Location
+	clock      clock.Clock
+
+	// Notion のステータスグループのキャッシュ（statusGroupsTTL の間は取得し直さない）
+	statusMu        sync.Mutex
+	statusGroups    statusMapping
+	statusFetchedAt time.Time
+}
+
+type Option func(*Client)
+
+// 1 リクエストあたりの取得件数を指定する。1〜100 の範囲外は無視される。
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		if size > 0 && size <= maxPageSize {
+			c.pageSize = size
+		}
+	}
+}
+
+// ページネーションで辿る最大リクエスト数を指定する。0 以下は無視される。
+func WithMaxPages(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxPages = n
+		}
+	}
+}
+
+// Notion のプロパティ名・型の対応表を指定する。空のフィールドはデフォルトで補完される。
+func WithPropertyMapping(m PropertyMapping) Option {
+	return func(c *Client) {
+		c.props = m.withDefaults()
+	}
+}
+
+// ステータス名とグループの対応を指定する。Status が select 型の場合や、
+// Notion のステータスグループとは別の扱いにしたいステータスがある場合に使う。
+func WithStatusMapping(m map[string]task.StatusGroup) Option {
+	return func(c *Client) {
+		c.statusOverrides = maps.Clone(m)
+	}
+}
+
+// 優先度のプロパティ値と task.Priority の対応を指定する。既定の対応（High/Medium/Low, 高/中/低）を置き換える。
+func WithPriorityMapping(m map[string]task.Priority) Option {
+	return func(c *Client) {
+		if len(m) > 0 {
+			c.priorities = maps.Clone(m)
+		}
+	}
+}
+
+// 429 や 5xx を受けたときの再試行方針を指定する。
+func WithRetryPolicy(p httpretry.Policy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// 日付フィルタの「今日」と、日付のみの値を解釈するタイムゾーンを指定する。
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		if loc != nil {
+			c.location = loc
+		}
+	}
 }
 
-func NewClient(apiToken, databaseID string) *Client {
-	return &Client{
+// 日付フィルタの「今日」の基準となる時計を指定する。
+func WithClock(c clock.Clock) Option {
+	return func(client *Client) {
+		if c != nil {
+			client.clock = c
+		}
+	}
+}
+
+func NewClient(apiToken, databaseID string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    notionBaseURL,
 		apiToken:   apiToken,
 		databaseID: databaseID,
+		pageSize:   maxPageSize,
+		maxPages:   defaultMaxPages,
+		props:      DefaultPropertyMapping(),
+		priorities: defaultPriorityMapping(),
+		retry:      httpretry.DefaultPolicy(),
+		location:   time.Local,
+		clock:      clock.System(nil),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Notion API でフィルタ条件を使って締切が近いタスクを取得する。
-// Status が Not Started または In Progress、かつ Due が指定日数以内のタスクを返す。
+// Status が完了グループ以外、かつ Due が指定日数以内のタスクを返す。
 func (c *Client) FetchTasksWithUpcomingDeadlines(ctx context.Context, daysBeforeDeadline int) ([]*task.Task, error) {
-	now := time.Now()
+	now := c.clock.Now().In(c.location)
 	endDate := now.AddDate(0, 0, daysBeforeDeadline)
 
-	filter := map[string]interface{}{
-		"and": []map[string]interface{}{
-			{
-				"property": "Due",
-				"date": map[string]interface{}{
-					"on_or_before": endDate.Format("2006-01-02"),
-				},
-			},
-			{
-				"property": "Due",
-				"date": map[string]interface{}{
-					"on_or_after": now.Format("2006-01-02"),
-				},
-			},
-			{
-				"or": []map[string]interface{}{
-					{
-						"property": "Status",
-						"status": map[string]string{
-							"equals": "Not Started",
-						},
-					},
-					{
-						"property": "Status",
-						"status": map[string]string{
-							"equals": "In Progress",
-						},
-					},
-				},
-			},
-		},
-	}
-
-	return c.queryDatabase(ctx, filter)
+	return c.queryIncomplete(ctx, []map[string]interface{}{
+		c.props.Due.filter(map[string]interface{}{
+			"on_or_before": endDate.Format("2006-01-02"),
+		}),
+		c.props.Due.filter(map[string]interface{}{
+			"on_or_after": now.Format("2006-01-02"),
+		}),
+	})
 }
 
 // Notion API でタスク種別が Study かつ未完了のタスクを取得する。
 func (c *Client) FetchIncompleteStudyTasks(ctx context.Context) ([]*task.Task, error) {
-	filter := map[string]interface{}{
-		"and": []map[string]interface{}{
-			{
-				"property": "タスク種別",
-				"select": map[string]string{
-					"equals": "Study",
-				},
-			},
-			{
-				"or": []map[string]interface{}{
-					{
-						"property": "Status",
-						"status": map[string]string{
-							"equals": "Not Started",
-						},
-					},
-					{
-						"property": "Status",
-						"status": map[string]string{
-							"equals": "In Progress",
-						},
-					},
-				},
-			},
-		},
-	}
-
-	return c.queryDatabase(ctx, filter)
+	return c.queryIncomplete(ctx, []map[string]interface{}{
+		c.props.TaskType.filter(map[string]string{
+			"equals": "Study",
+		}),
+	})
 }
 
-func (c *Client) queryDatabase(ctx context.Context, filter map[string]interface{}) ([]*task.Task, error) {
-	reqBody := map[string]interface{}{
-		"filter": filter,
+// Notion API で締切を過ぎた未完了のタスクを取得する。
+// maxDaysOverdue が 0 より大きい場合は、その日数以内に締切を過ぎたタスクに限る。
+func (c *Client) FetchOverdueTasks(ctx context.Context, maxDaysOverdue int) ([]*task.Task, error) {
+	now := c.clock.Now().In(c.location)
+
+	conditions := []map[string]interface{}{
+		c.props.Due.filter(map[string]interface{}{
+			"before": now.Format("2006-01-02"),
+		}),
+	}
+	if maxDaysOverdue > 0 {
+		conditions = append(conditions, c.props.Due.filter(map[string]interface{}{
+			"on_or_after": now.AddDate(0, 0, -maxDaysOverdue).Format("2006-01-02"),
+		}))
 	}
 
-	body, err := json.Marshal(reqBody)
+	return c.queryIncomplete(ctx, conditions)
+}
+
+// Notion API で ID のタスク（ページ）を 1 件取得する。
+func (c *Client) FetchTask(ctx context.Context, taskID string) (*task.Task, error) {
+	statuses, err := c.statusMapping(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, err
 	}
-
-	url := fmt.Sprintf("%s/databases/%s/query", notionBaseURL, c.databaseID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	p, err := c.fetchPage(ctx, taskID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
-	req.Header.Set("Notion-Version", notionAPIVersion)
-	req.Header.Set("Content-Type", "application/json")
+	projectNames := make(map[string]string)
+	if c.props.Project.Type == PropertyTypeRelation {
+		if id := p.property(c.props.Project).firstRelationID(); id != "" {
+			name, err := c.fetchPageTitle(ctx, id)
+			if err != nil {
+				name = "Personal"
+			}
+			projectNames[id] = name
+		}
+	}
+	return c.pageToTask(*p, projectNames, statuses), nil
+}
 
-	resp, err := c.httpClient.Do(req)
+// conditions に加えて、Status が完了グループのタスクを除外する条件でクエリする。
+func (c *Client) queryIncomplete(ctx context.Context, conditions []map[string]interface{}) ([]*task.Task, error) {
+	statuses, err := c.statusMapping(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("notion API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	if f := statuses.incompleteFilter(c.props.Status); f != nil {
+		conditions = append(conditions, f)
 	}
 
-	var result queryResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return c.queryDatabase(ctx, map[string]interface{}{"and": conditions}, statuses)
+}
+
+// has_more が false になるまで next_cursor を辿り、フィルタに一致する全ページを取得する。
+// maxPages に達しても続きがある場合は、取りこぼしを避けるためエラーを返す。
+func (c *Client) queryDatabase(ctx context.Context, filter map[string]interface{}, statuses statusMapping) ([]*task.Task, error) {
+	var pages []page
+	cursor := ""
+	for i := 0; ; i++ {
+		if i >= c.maxPages {
+			return nil, fmt.Errorf("notion query exceeded max pages (%d)", c.maxPages)
+		}
+
+		result, err := c.queryDatabasePage(ctx, filter, cursor)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, result.Results...)
+
+		if !result.HasMore || result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
 	}
 
 	projectIDs := make(map[string]bool)
-	for _, p := range result.Results {
-		if len(p.Properties.Project.Relation) > 0 {
-			projectIDs[p.Properties.Project.Relation[0].ID] = true
+	if c.props.Project.Type == PropertyTypeRelation {
+		for _, p := range pages {
+			if id := p.property(c.props.Project).firstRelationID(); id != "" {
+				projectIDs[id] = true
+			}
 		}
 	}
 
@@ -158,13 +258,58 @@ func (c *Client) queryDatabase(ctx context.Context, filter map[string]interface{
 		}
 	}
 
-	return c.convertToTasks(result.Results, projectNames), nil
+	return c.convertToTasks(pages, projectNames, statuses), nil
+}
+
+func (c *Client) queryDatabasePage(ctx context.Context, filter map[string]interface{}, cursor string) (*queryResponse, error) {
+	reqBody := map[string]interface{}{
+		"filter":    filter,
+		"page_size": c.pageSize,
+	}
+	if cursor != "" {
+		reqBody["start_cursor"] = cursor
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/databases/%s/query", c.baseURL, c.databaseID)
+	// データベースのクエリは読み取りのみなので、POST でも再送して問題ない
+	resp, err := c.retry.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		req.Header.Set("Notion-Version", notionAPIVersion)
+		req.Header.Set("Content-Type", "application/json")
+
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("notion API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+
+	var result queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
 }
 
-func (c *Client) convertToTasks(pages []page, projectNames map[string]string) []*task.Task {
+func (c *Client) convertToTasks(pages []page, projectNames map[string]string, statuses statusMapping) []*task.Task {
 	tasks := make([]*task.Task, 0, len(pages))
 	for _, p := range pages {
-		t := c.pageToTask(p, projectNames)
+		t := c.pageToTask(p, projectNames, statuses)
 		if t != nil {
 			tasks = append(tasks, t)
 		}
@@ -172,141 +317,134 @@ func (c *Client) convertToTasks(pages []page, projectNames map[string]string) []
 	return tasks
 }
 
-func (c *Client) pageToTask(p page, projectNames map[string]string) *task.Task {
-	name := ""
-	if title := p.Properties.TaskName; title.Title != nil && len(title.Title) > 0 {
-		name = title.Title[0].PlainText
-	}
+// statuses が nil の場合は既定の対応でステータスのグループを決める。
+func (c *Client) pageToTask(p page, projectNames map[string]string, statuses statusMapping) *task.Task {
+	name := p.property(c.props.TaskName).text()
+	due := p.property(c.props.Due)
+	dueDate := due.date(c.location)
 
-	var dueDate *time.Time
-	if p.Properties.Due.Date != nil && p.Properties.Due.Date.Start != "" {
-		dueDate = parseDueDate(p.Properties.Due.Date.Start)
-	}
-
-	status := task.StatusNotStarted
-	if p.Properties.Status.Status != nil {
-		switch p.Properties.Status.Status.Name {
-		case "In Progress":
-			status = task.StatusInProgress
-		case "Done":
-			status = task.StatusDone
-		case "Archived":
-			status = task.StatusArchived
-		}
+	if statuses == nil {
+		statuses = defaultStatusMapping()
 	}
+	status := p.property(c.props.Status).text()
 
 	projectName := "Personal"
-	if len(p.Properties.Project.Relation) > 0 {
-		projectName = projectNames[p.Properties.Project.Relation[0].ID]
+	project := p.property(c.props.Project)
+	if c.props.Project.Type == PropertyTypeRelation {
+		if id := project.firstRelationID(); id != "" {
+			projectName = projectNames[id]
+		}
+	} else if text := project.text(); text != "" {
+		projectName = text
 	}
 
-	t := task.NewTask(p.ID, name, projectName, dueDate, status)
+	t := task.NewTask(p.ID, name, projectName, dueDate, task.Status(status))
+	t.StatusGroup = statuses.group(status)
+	t.DueHasTime = dueDate != nil && due.dateHasTime()
+	t.URL = p.URL
 	// Map reading specific properties
-	if p.Properties.TaskType.Select != nil {
-		t.TaskType = p.Properties.TaskType.Select.Name
+	t.TaskType = p.property(c.props.TaskType).text()
+	t.StartDate = p.property(c.props.StartDate).date(c.location)
+	if n, ok := p.property(c.props.TotalPages).number(); ok {
+		t.TotalPages = n
 	}
-	if p.Properties.StartDate.Date != nil && p.Properties.StartDate.Date.Start != "" {
-		t.StartDate = parseDueDate(p.Properties.StartDate.Date.Start)
+	if n, ok := p.property(c.props.ReadPages).number(); ok {
+		t.ReadPages = n
 	}
-	if p.Properties.TotalPages.Number != nil {
-		t.TotalPages = int(*p.Properties.TotalPages.Number)
-	}
-	if p.Properties.ReadPages.Number != nil {
-		t.ReadPages = int(*p.Properties.ReadPages.Number)
+	if n, ok := p.property(c.props.PagesPerDay).number(); ok {
+		t.PagesPerDay = n
 	}
+	t.Priority = c.priorities[p.property(c.props.Priority).text()]
+	t.Muted = p.property(c.props.Mute).checked()
 
 	return t
 }
 
-type queryResponse struct {
-	Results []page `json:"results"`
-}
-
-type page struct {
-	ID         string     `json:"id"`
-	Properties properties `json:"properties"`
-}
-
-type properties struct {
-	TaskName   titleProperty    `json:"Task name"`
-	Due        dateProperty     `json:"Due"`
-	Status     statusProperty   `json:"Status"`
-	Project    relationProperty `json:"Project"`
-	TaskType   selectProperty   `json:"タスク種別"`
-	StartDate  dateProperty     `json:"開始日"`
-	TotalPages numberProperty   `json:"総ページ数"`
-	ReadPages  numberProperty   `json:"読んだページ数"`
-}
-
-type relationProperty struct {
-	Relation []relationValue `json:"relation"`
-}
-
-type relationValue struct {
-	ID string `json:"id"`
-}
-
-type titleProperty struct {
-	Title []richText `json:"title"`
-}
-
-type richText struct {
-	PlainText string `json:"plain_text"`
-}
-
-type dateProperty struct {
-	Date *dateValue `json:"date"`
-}
-
-type dateValue struct {
-	Start string `json:"start"`
-}
-
-type statusProperty struct {
-	Status *statusValue `json:"status"`
-}
-
-type statusValue struct {
-	Name string `json:"name"`
+func defaultPriorityMapping() map[string]task.Priority {
+	return map[string]task.Priority{
+		"High":   task.PriorityHigh,
+		"Medium": task.PriorityMedium,
+		"Low":    task.PriorityLow,
+		"高":      task.PriorityHigh,
+		"中":      task.PriorityMedium,
+		"低":      task.PriorityLow,
+	}
 }
 
-type selectProperty struct {
-	Select *selectValue `json:"select"`
+type queryResponse struct {
+	Results    []page `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
 }
 
-type selectValue struct {
-	Name string `json:"name"`
+type page struct {
+	ID         string                   `json:"id"`
+	URL        string                   `json:"url"`
+	Properties map[string]propertyValue `json:"properties"`
 }
 
-type numberProperty struct {
-	Number *float64 `json:"number"`
+// 対応表に従ってプロパティ値を取り出す。存在しない場合はゼロ値を返す。
+func (p page) property(prop Property) propertyValue {
+	return p.Properties[prop.Name]
 }
 
-// Notion の日付形式をパースする。
-// - 日付のみ: "2026-02-10" → UTC 00:00:00 として解釈
-// - 時刻付き: "2026-02-10T15:00:00.000+09:00" → 元のタイムゾーンを保持
-func parseDueDate(s string) *time.Time {
+// Notion の日付形式をパースする。日数計算が loc の暦で行われるよう、結果は loc に揃える。
+// - 日付のみ: "2026-02-10" → loc の 00:00:00 として解釈
+// - 時刻付き: "2026-02-10T15:00:00.000+09:00" → 同じ時刻を loc で表す
+func parseDueDate(s string, loc *time.Location) *time.Time {
 	// まず時刻付き形式（RFC3339）を試行
 	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		t = t.In(loc)
 		return &t
 	}
 	// 次に日付のみ形式を試行
-	if t, err := time.Parse("2006-01-02", s); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
 		return &t
 	}
 	return nil
 }
 
-func (c *Client) fetchPageTitle(ctx context.Context, pageID string) (string, error) {
-	url := fmt.Sprintf("%s/pages/%s", notionBaseURL, pageID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+func (c *Client) fetchPage(ctx context.Context, pageID string) (*page, error) {
+	url := fmt.Sprintf("%s/pages/%s", c.baseURL, pageID)
+	resp, err := c.retry.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		req.Header.Set("Notion-Version", notionAPIVersion)
+
+		return c.httpClient.Do(req)
+	})
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("notion API error: status=%d, body=%s", resp.StatusCode, string(respBody))
 	}
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
-	req.Header.Set("Notion-Version", notionAPIVersion)
 
-	resp, err := c.httpClient.Do(req)
+	var p page
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &p, nil
+}
+
+func (c *Client) fetchPageTitle(ctx context.Context, pageID string) (string, error) {
+	url := fmt.Sprintf("%s/pages/%s", c.baseURL, pageID)
+	resp, err := c.retry.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		req.Header.Set("Notion-Version", notionAPIVersion)
+
+		return c.httpClient.Do(req)
+	})
 	if err != nil {
 		return "", err
 	}
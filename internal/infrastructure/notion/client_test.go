@@ -3,9 +3,15 @@ package notion
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/clock"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
 )
 
 func TestClient_FetchTasksWithUpcomingDeadlines(t *testing.T) {
@@ -13,36 +19,24 @@ func TestClient_FetchTasksWithUpcomingDeadlines(t *testing.T) {
 		Results: []page{
 			{
 				ID: "task-1",
-				Properties: properties{
-					TaskName: titleProperty{
-						Title: []richText{{PlainText: "Test Task 1"}},
-					},
-					Due: dateProperty{
-						Date: &dateValue{Start: "2026-02-10"},
-					},
-					Status: statusProperty{
-						Status: &statusValue{Name: "Not Started"},
-					},
+				Properties: map[string]propertyValue{
+					"Task name": {Title: []richText{{PlainText: "Test Task 1"}}},
+					"Due":       {Date: &dateValue{Start: "2026-02-10"}},
+					"Status":    {Status: &statusValue{Name: "Not Started"}},
 				},
 			},
 			{
 				ID: "task-2",
-				Properties: properties{
-					TaskName: titleProperty{
-						Title: []richText{{PlainText: "Test Task 2"}},
-					},
-					Due: dateProperty{
-						Date: &dateValue{Start: "2026-02-11"},
-					},
-					Status: statusProperty{
-						Status: &statusValue{Name: "In Progress"},
-					},
+				Properties: map[string]propertyValue{
+					"Task name": {Title: []richText{{PlainText: "Test Task 2"}}},
+					"Due":       {Date: &dateValue{Start: "2026-02-11"}},
+					"Status":    {Status: &statusValue{Name: "In Progress"}},
 				},
 			},
 		},
 	}
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			t.Errorf("expected POST request, got %s", r.Method)
 		}
@@ -55,22 +49,13 @@ func TestClient_FetchTasksWithUpcomingDeadlines(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := &Client{
-		httpClient: server.Client(),
-		apiToken:   "test-token",
-		databaseID: "test-db-id",
-	}
-
-	// Override base URL for testing (using a simple approach)
-	originalURL := notionBaseURL
-	defer func() { /* restore would happen here if we modified the constant */ }()
-	_ = originalURL
+	client := NewClient("test-token", "test-db-id")
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
 
 	tasks, err := client.FetchTasksWithUpcomingDeadlines(context.Background(), 3)
 	if err != nil {
-		// Expected to fail because we can't override the base URL
-		// In production, we would use dependency injection for the base URL
-		t.Skip("Skipping test - need to implement base URL injection for testability")
+		t.Fatalf("unexpected error: %v", err)
 	}
 
 	if len(tasks) != 2 {
@@ -78,25 +63,188 @@ func TestClient_FetchTasksWithUpcomingDeadlines(t *testing.T) {
 	}
 }
 
+func TestClient_queryDatabase_Pagination(t *testing.T) {
+	pages := [][]page{
+		{{ID: "task-1"}, {ID: "task-2"}},
+		{{ID: "task-3"}, {ID: "task-4"}},
+		{{ID: "task-5"}},
+	}
+
+	var requests []map[string]interface{}
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+
+		idx := len(requests) - 1
+		resp := queryResponse{Results: pages[idx]}
+		if idx < len(pages)-1 {
+			resp.HasMore = true
+			resp.NextCursor = fmt.Sprintf("cursor-%d", idx+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id", WithPageSize(2))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	tasks, err := client.FetchIncompleteStudyTasks(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tasks) != 5 {
+		t.Fatalf("expected 5 tasks, got %d", len(tasks))
+	}
+	for i, task := range tasks {
+		if want := fmt.Sprintf("task-%d", i+1); task.ID != want {
+			t.Errorf("tasks[%d].ID = %s, want %s", i, task.ID, want)
+		}
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if _, ok := requests[0]["start_cursor"]; ok {
+		t.Errorf("first request should not have start_cursor")
+	}
+	if requests[1]["start_cursor"] != "cursor-1" || requests[2]["start_cursor"] != "cursor-2" {
+		t.Errorf("unexpected cursors: %v, %v", requests[1]["start_cursor"], requests[2]["start_cursor"])
+	}
+	if requests[0]["page_size"] != float64(2) {
+		t.Errorf("expected page_size 2, got %v", requests[0]["page_size"])
+	}
+}
+
+func TestClient_queryDatabase_MaxPages(t *testing.T) {
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{
+			Results:    []page{{ID: "task"}},
+			HasMore:    true,
+			NextCursor: "next",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id", WithMaxPages(3))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	if _, err := client.FetchIncompleteStudyTasks(context.Background()); err == nil {
+		t.Fatal("expected error when exceeding max pages, got nil")
+	}
+}
+
+func TestClient_FetchOverdueTasks_Filter(t *testing.T) {
+	var filter map[string]interface{}
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter = body["filter"].(map[string]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id")
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	if _, err := client.FetchOverdueTasks(context.Background(), 14); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := filter["and"].([]interface{})
+	if len(conditions) != 3 {
+		t.Fatalf("expected 3 conditions, got %d", len(conditions))
+	}
+	before := conditions[0].(map[string]interface{})["date"].(map[string]interface{})
+	if _, ok := before["before"]; !ok {
+		t.Errorf("expected 'before' date condition, got %v", before)
+	}
+	lookback := conditions[1].(map[string]interface{})["date"].(map[string]interface{})
+	if _, ok := lookback["on_or_after"]; !ok {
+		t.Errorf("expected 'on_or_after' lookback condition, got %v", lookback)
+	}
+
+	if _, err := client.FetchOverdueTasks(context.Background(), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(filter["and"].([]interface{})); n != 2 {
+		t.Errorf("expected no lookback condition when maxDaysOverdue is 0, got %d conditions", n)
+	}
+}
+
+func TestClient_FetchTasksWithUpcomingDeadlines_Timezone(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	var filter map[string]interface{}
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter = body["filter"].(map[string]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id", WithLocation(jst))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+	// UTC では 2/28 だが、JST では 3/1 08:30
+	client.clock = clock.Fixed(time.Date(2026, 2, 28, 23, 30, 0, 0, time.UTC))
+
+	if _, err := client.FetchTasksWithUpcomingDeadlines(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := filter["and"].([]interface{})
+	until := conditions[0].(map[string]interface{})["date"].(map[string]interface{})["on_or_before"]
+	from := conditions[1].(map[string]interface{})["date"].(map[string]interface{})["on_or_after"]
+	if from != "2026-03-01" || until != "2026-03-04" {
+		t.Errorf("expected range 2026-03-01..2026-03-04, got %v..%v", from, until)
+	}
+}
+
+func TestParseDueDate_Timezone(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	dateOnly := parseDueDate("2026-03-01", jst)
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, jst); !dateOnly.Equal(want) {
+		t.Errorf("date-only: got %v, want %v", dateOnly, want)
+	}
+
+	withTime := parseDueDate("2026-02-28T20:00:00.000Z", jst)
+	if withTime.Location() != jst || withTime.Format("2006-01-02 15:04") != "2026-03-01 05:00" {
+		t.Errorf("RFC3339: got %v, want 2026-03-01 05:00 JST", withTime)
+	}
+}
+
 func TestClient_pageToTask(t *testing.T) {
-	client := &Client{}
+	client := NewClient("test-token", "test-db-id")
 
 	p := page{
 		ID: "task-123",
-		Properties: properties{
-			TaskName: titleProperty{
-				Title: []richText{{PlainText: "My Task"}},
-			},
-			Due: dateProperty{
-				Date: &dateValue{Start: "2026-02-15"},
-			},
-			Status: statusProperty{
-				Status: &statusValue{Name: "In Progress"},
-			},
+		Properties: map[string]propertyValue{
+			"Task name": {Title: []richText{{PlainText: "My Task"}}},
+			"Due":       {Date: &dateValue{Start: "2026-02-15"}},
+			"Status":    {Status: &statusValue{Name: "In Progress"}},
 		},
 	}
 
-	task := client.pageToTask(p, nil)
+	task := client.pageToTask(p, nil, nil)
 
 	if task.ID != "task-123" {
 		t.Errorf("expected ID 'task-123', got '%s'", task.ID)
@@ -110,4 +258,464 @@ func TestClient_pageToTask(t *testing.T) {
 	if task.DueDate.Format("2006-01-02") != "2026-02-15" {
 		t.Errorf("expected DueDate '2026-02-15', got '%s'", task.DueDate.Format("2006-01-02"))
 	}
+	if task.DueHasTime {
+		t.Error("expected date-only due date not to have a time")
+	}
+
+	p.Properties["Due"] = propertyValue{Date: &dateValue{Start: "2026-02-15T18:00:00.000+09:00"}}
+	if !client.pageToTask(p, nil, nil).DueHasTime {
+		t.Error("expected RFC3339 due date to have a time")
+	}
+	if task.Muted {
+		t.Error("expected task without the mute property not to be muted")
+	}
+	p.Properties["通知しない"] = propertyValue{Type: PropertyTypeCheckbox, Checkbox: true}
+	if !client.pageToTask(p, nil, nil).Muted {
+		t.Error("expected checked mute property to mute the task")
+	}
+}
+
+func TestClient_pageToTask_Priority(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		value *selectValue
+		want  task.Priority
+	}{
+		{name: "default english", value: &selectValue{Name: "High"}, want: task.PriorityHigh},
+		{name: "default japanese", value: &selectValue{Name: "低"}, want: task.PriorityLow},
+		{name: "not set", value: nil, want: task.PriorityNone},
+		{name: "unknown value", value: &selectValue{Name: "Someday"}, want: task.PriorityNone},
+		{
+			name:  "custom mapping",
+			opts:  []Option{WithPriorityMapping(map[string]task.Priority{"P1": task.PriorityHigh})},
+			value: &selectValue{Name: "P1"},
+			want:  task.PriorityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test-token", "test-db-id", tt.opts...)
+			p := page{ID: "task-1", Properties: map[string]propertyValue{
+				"Priority": {Select: tt.value},
+			}}
+			if got := client.pageToTask(p, nil, nil).Priority; got != tt.want {
+				t.Errorf("Priority = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_pageToTask_CustomPropertyMapping(t *testing.T) {
+	client := NewClient("test-token", "test-db-id", WithPropertyMapping(PropertyMapping{
+		TaskName:   Property{Name: "Name"},
+		Due:        Property{Name: "Deadline"},
+		Status:     Property{Name: "State", Type: PropertyTypeSelect},
+		Project:    Property{Name: "Area", Type: PropertyTypeSelect},
+		TaskType:   Property{Name: "Kind"},
+		TotalPages: Property{Name: "Pages"},
+		ReadPages:  Property{Name: "Pages read"},
+	}))
+
+	total, read := float64(300), float64(120)
+	p := page{
+		ID: "task-456",
+		Properties: map[string]propertyValue{
+			"Name":       {Title: []richText{{PlainText: "Read a book"}}},
+			"Deadline":   {Date: &dateValue{Start: "2026-03-01"}},
+			"State":      {Select: &selectValue{Name: "In Progress"}},
+			"Area":       {Select: &selectValue{Name: "Work"}},
+			"Kind":       {Select: &selectValue{Name: "Study"}},
+			"開始日":        {Date: &dateValue{Start: "2026-02-01"}},
+			"Pages":      {Number: &total},
+			"Pages read": {Number: &read},
+		},
+	}
+
+	got := client.pageToTask(p, nil, nil)
+
+	if got.Name != "Read a book" {
+		t.Errorf("expected Name 'Read a book', got '%s'", got.Name)
+	}
+	if got.DueDate == nil || got.DueDate.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("unexpected DueDate: %v", got.DueDate)
+	}
+	if got.Status != task.StatusInProgress {
+		t.Errorf("expected StatusInProgress, got %s", got.Status)
+	}
+	if got.ProjectName != "Work" {
+		t.Errorf("expected ProjectName 'Work', got '%s'", got.ProjectName)
+	}
+	if got.TaskType != "Study" {
+		t.Errorf("expected TaskType 'Study', got '%s'", got.TaskType)
+	}
+	if got.StartDate == nil || got.StartDate.Format("2006-01-02") != "2026-02-01" {
+		t.Errorf("unexpected StartDate: %v", got.StartDate)
+	}
+	if got.TotalPages != 300 || got.ReadPages != 120 {
+		t.Errorf("expected pages 120/300, got %d/%d", got.ReadPages, got.TotalPages)
+	}
+}
+
+func TestClient_FetchIncompleteStudyTasks_CustomPropertyMapping(t *testing.T) {
+	var filter map[string]interface{}
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter = body["filter"].(map[string]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id", WithPropertyMapping(PropertyMapping{
+		Status:   Property{Name: "State", Type: PropertyTypeSelect},
+		TaskType: Property{Name: "Kind"},
+	}))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	if _, err := client.FetchIncompleteStudyTasks(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := filter["and"].([]interface{})
+	typeFilter := conditions[0].(map[string]interface{})
+	if typeFilter["property"] != "Kind" || typeFilter["select"] == nil {
+		t.Errorf("unexpected task type filter: %v", typeFilter)
+	}
+	statusFilter := conditions[1].(map[string]interface{})["and"].([]interface{})[0].(map[string]interface{})
+	if statusFilter["property"] != "State" || statusFilter["select"] == nil {
+		t.Errorf("unexpected status filter: %v", statusFilter)
+	}
+}
+
+// testStatusSchema は既定のステータスに、グループ未設定の名前では判別できない "Blocked" を加えたスキーマ。
+var testStatusSchema = statusSchema("Status", [][]string{
+	{"Not Started", "Blocked"},
+	{"In Progress"},
+	{"Done", "Archived"},
+})
+
+// statusSchema は GET /databases/{id} のレスポンスのうち、ステータスプロパティ部分を組み立てる。
+// groups は To-do, In progress, Complete の順に各グループのステータス名を並べたもの。
+func statusSchema(property string, groups [][]string) map[string]interface{} {
+	var options, groupList []map[string]interface{}
+	for i, names := range groups {
+		var ids []string
+		for j, name := range names {
+			id := fmt.Sprintf("opt-%d-%d", i, j)
+			options = append(options, map[string]interface{}{"id": id, "name": name})
+			ids = append(ids, id)
+		}
+		groupList = append(groupList, map[string]interface{}{"id": fmt.Sprintf("group-%d", i), "name": fmt.Sprintf("Group %d", i), "option_ids": ids})
+	}
+	return map[string]interface{}{
+		"properties": map[string]interface{}{
+			property: map[string]interface{}{
+				"type":   "status",
+				"status": map[string]interface{}{"options": options, "groups": groupList},
+			},
+		},
+	}
+}
+
+// newNotionServer はデータベースのスキーマ取得に testStatusSchema を返し、それ以外を handler に任せる。
+func newNotionServer(handler http.Handler) *httptest.Server {
+	return newNotionServerWithSchema(testStatusSchema, handler)
+}
+
+func newNotionServerWithSchema(schema map[string]interface{}, handler http.Handler) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(schema)
+	})
+	mux.Handle("/", handler)
+	return httptest.NewServer(mux)
+}
+
+func TestClient_StatusGroups(t *testing.T) {
+	schema := statusSchema("Status", [][]string{
+		{"未着手", "Blocked"},
+		{"進行中", "Waiting"},
+		{"完了"},
+	})
+
+	var filter map[string]interface{}
+	server := newNotionServerWithSchema(schema, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter = body["filter"].(map[string]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{Results: []page{
+			{ID: "1", Properties: map[string]propertyValue{"Status": {Status: &statusValue{Name: "未着手"}}}},
+			{ID: "2", Properties: map[string]propertyValue{"Status": {Status: &statusValue{Name: "Waiting"}}}},
+			{ID: "3", Properties: map[string]propertyValue{"Status": {Status: &statusValue{Name: "Unlisted"}}}},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id")
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	tasks, err := client.FetchIncompleteStudyTasks(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantGroups := []task.StatusGroup{task.StatusGroupToDo, task.StatusGroupInProgress, task.StatusGroupToDo}
+	for i, want := range wantGroups {
+		if tasks[i].StatusGroup != want {
+			t.Errorf("tasks[%d] (%s): StatusGroup = %s, want %s", i, tasks[i].Status, tasks[i].StatusGroup, want)
+		}
+	}
+
+	// 完了グループのステータスだけを除外する（既定の Done / Archived も念のため除外される）
+	conditions := filter["and"].([]interface{})
+	var excluded []string
+	for _, c := range conditions[1].(map[string]interface{})["and"].([]interface{}) {
+		excluded = append(excluded, c.(map[string]interface{})["status"].(map[string]interface{})["does_not_equal"].(string))
+	}
+	if fmt.Sprint(excluded) != "[Archived Done 完了]" {
+		t.Errorf("unexpected excluded statuses: %v", excluded)
+	}
+}
+
+type steppingClock struct {
+	now time.Time
+}
+
+func (c *steppingClock) Now() time.Time {
+	return c.now
+}
+
+func TestClient_StatusGroups_Cached(t *testing.T) {
+	schema := statusSchema("Status", [][]string{{"未着手"}, {"進行中"}, {"完了"}})
+
+	var schemaFetches int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		schemaFetches++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(schema)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	clk := &steppingClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	client := NewClient("test-token", "test-db-id", WithClock(clk))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	ctx := context.Background()
+	fetchAll := func() {
+		t.Helper()
+		if _, err := client.FetchTasksWithUpcomingDeadlines(ctx, 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.FetchOverdueTasks(ctx, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.FetchIncompleteStudyTasks(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	fetchAll()
+	if schemaFetches != 1 {
+		t.Errorf("expected schema to be fetched once, got %d", schemaFetches)
+	}
+
+	// 期間が過ぎたら取得し直す
+	clk.now = clk.now.Add(statusGroupsTTL)
+	fetchAll()
+	if schemaFetches != 2 {
+		t.Errorf("expected schema to be fetched again after the TTL, got %d", schemaFetches)
+	}
+}
+
+func TestClient_StatusMapping_Select(t *testing.T) {
+	var filter map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("select status should not fetch the database schema, got %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter = body["filter"].(map[string]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(queryResponse{Results: []page{
+			{ID: "1", Properties: map[string]propertyValue{"State": {Select: &selectValue{Name: "Someday"}}}},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id",
+		WithPropertyMapping(PropertyMapping{Status: Property{Name: "State", Type: PropertyTypeSelect}}),
+		WithStatusMapping(map[string]task.StatusGroup{
+			"Someday": task.StatusGroupComplete,
+			"Done":    task.StatusGroupToDo,
+		}),
+	)
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	tasks, err := client.FetchIncompleteStudyTasks(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].StatusGroup != task.StatusGroupComplete {
+		t.Errorf("expected Someday to be mapped to complete, got %+v", tasks)
+	}
+
+	conditions := filter["and"].([]interface{})
+	var excluded []string
+	for _, c := range conditions[1].(map[string]interface{})["and"].([]interface{}) {
+		excluded = append(excluded, c.(map[string]interface{})["select"].(map[string]interface{})["does_not_equal"].(string))
+	}
+	if fmt.Sprint(excluded) != "[Archived Someday]" {
+		t.Errorf("unexpected excluded statuses: %v", excluded)
+	}
+}
+
+func TestPropertyMapping_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping PropertyMapping
+		wantErr bool
+	}{
+		{name: "default mapping", mapping: PropertyMapping{}, wantErr: false},
+		{name: "select status", mapping: PropertyMapping{Status: Property{Type: PropertyTypeSelect}}, wantErr: false},
+		{name: "number due is invalid", mapping: PropertyMapping{Due: Property{Type: PropertyTypeNumber}}, wantErr: true},
+		{name: "unknown type", mapping: PropertyMapping{TaskName: Property{Type: "formula"}}, wantErr: true},
+		{name: "select mute is invalid", mapping: PropertyMapping{Mute: Property{Type: PropertyTypeSelect}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mapping.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 複数のフィールドが不正でも、毎回定義順で最初のフィールドのエラーになる
+	invalid := PropertyMapping{ReadPages: Property{Type: PropertyTypeDate}, Due: Property{Type: PropertyTypeNumber}, TaskType: Property{Type: PropertyTypeNumber}}
+	for range 20 {
+		if err := invalid.Validate(); err == nil || !strings.HasPrefix(err.Error(), "notion.properties.due:") {
+			t.Fatalf("Validate() = %v, want the error for due", err)
+		}
+	}
+}
+
+func TestClient_UpdateProperties(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	var bodies []string
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/pages/task-1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body["properties"])
+		bodies = append(bodies, string(data))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object": "page", "id": "task-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id", WithLocation(jst))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	ctx := context.Background()
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for _, err := range []error{
+		client.UpdateStatus(ctx, "task-1", task.StatusDone),
+		client.UpdateReadPages(ctx, "task-1", 120),
+		client.PostponeDue(ctx, "task-1", due, false),
+		client.PostponeDue(ctx, "task-1", due, true),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := []string{
+		`{"Status":{"status":{"name":"Done"}}}`,
+		`{"読んだページ数":{"number":120}}`,
+		`{"Due":{"date":{"start":"2026-03-02"}}}`,
+		`{"Due":{"date":{"start":"2026-03-02T18:00:00+09:00"}}}`,
+	}
+	if fmt.Sprint(bodies) != fmt.Sprint(want) {
+		t.Errorf("unexpected request bodies:\n got %v\nwant %v", bodies, want)
+	}
+}
+
+func TestClient_FetchTask(t *testing.T) {
+	total, read := 300.0, 120.0
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/pages/task-1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page{ID: "task-1", Properties: map[string]propertyValue{
+			"Task name": {Title: []richText{{PlainText: "Go言語"}}},
+			"Due":       {Date: &dateValue{Start: "2026-03-10"}},
+			"Status":    {Status: &statusValue{Name: "Blocked"}},
+			"総ページ数":     {Number: &total},
+			"読んだページ数":   {Number: &read},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id", WithLocation(time.UTC))
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	got, err := client.FetchTask(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "Go言語" || got.ReadPages != 120 || got.TotalPages != 300 {
+		t.Errorf("unexpected task: %+v", got)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DueDate = %v", got.DueDate)
+	}
+	if got.StatusGroup != task.StatusGroupToDo {
+		t.Errorf("StatusGroup = %q, want %q from the schema", got.StatusGroup, task.StatusGroupToDo)
+	}
+}
+
+func TestClient_UpdateStatus_Error(t *testing.T) {
+	server := newNotionServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object": "error", "code": "object_not_found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "test-db-id")
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	if err := client.UpdateStatus(context.Background(), "missing", task.StatusDone); err == nil {
+		t.Fatal("expected error for 404 response")
+	}
 }
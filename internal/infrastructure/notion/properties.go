@@ -0,0 +1,227 @@
+package notion
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Notion のプロパティ型。フィルタ条件のキーとしてもそのまま使われる。
+const (
+	PropertyTypeTitle    = "title"
+	PropertyTypeRichText = "rich_text"
+	PropertyTypeDate     = "date"
+	PropertyTypeStatus   = "status"
+	PropertyTypeSelect   = "select"
+	PropertyTypeRelation = "relation"
+	PropertyTypeNumber   = "number"
+	PropertyTypeCheckbox = "checkbox"
+)
+
+// Property は task.Task のフィールドに対応する Notion プロパティの名前と型。
+type Property struct {
+	Name string
+	Type string
+}
+
+// 型のみ指定された場合に Name だけを補うため、空のフィールドは def で埋める。
+func (p Property) orDefault(def Property) Property {
+	if p.Name == "" {
+		p.Name = def.Name
+	}
+	if p.Type == "" {
+		p.Type = def.Type
+	}
+	return p
+}
+
+// filter は Notion API のプロパティフィルタを組み立てる。
+// 例: {"property": "Due", "date": {"on_or_after": "2026-02-10"}}
+func (p Property) filter(condition interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"property": p.Name,
+		p.Type:     condition,
+	}
+}
+
+// PropertyMapping は task.Task の各フィールドと Notion プロパティの対応表。
+type PropertyMapping struct {
+	TaskName   Property
+	Due        Property
+	Status     Property
+	Project    Property
+	TaskType   Property
+	StartDate  Property
+	TotalPages Property
+	ReadPages  Property
+	// タスクごとの読書ペース（ページ/日）。データベースに存在しない場合は無視される
+	PagesPerDay Property
+	// 優先度。データベースに存在しない場合は PriorityNone になる
+	Priority Property
+	// チェックすると通知しないチェックボックス。データベースに存在しない場合は無視される
+	Mute Property
+}
+
+func DefaultPropertyMapping() PropertyMapping {
+	return PropertyMapping{
+		TaskName:    Property{Name: "Task name", Type: PropertyTypeTitle},
+		Due:         Property{Name: "Due", Type: PropertyTypeDate},
+		Status:      Property{Name: "Status", Type: PropertyTypeStatus},
+		Project:     Property{Name: "Project", Type: PropertyTypeRelation},
+		TaskType:    Property{Name: "タスク種別", Type: PropertyTypeSelect},
+		StartDate:   Property{Name: "開始日", Type: PropertyTypeDate},
+		TotalPages:  Property{Name: "総ページ数", Type: PropertyTypeNumber},
+		ReadPages:   Property{Name: "読んだページ数", Type: PropertyTypeNumber},
+		PagesPerDay: Property{Name: "1日のページ数", Type: PropertyTypeNumber},
+		Priority:    Property{Name: "Priority", Type: PropertyTypeSelect},
+		Mute:        Property{Name: "通知しない", Type: PropertyTypeCheckbox},
+	}
+}
+
+func (m PropertyMapping) withDefaults() PropertyMapping {
+	def := DefaultPropertyMapping()
+	return PropertyMapping{
+		TaskName:    m.TaskName.orDefault(def.TaskName),
+		Due:         m.Due.orDefault(def.Due),
+		Status:      m.Status.orDefault(def.Status),
+		Project:     m.Project.orDefault(def.Project),
+		TaskType:    m.TaskType.orDefault(def.TaskType),
+		StartDate:   m.StartDate.orDefault(def.StartDate),
+		TotalPages:  m.TotalPages.orDefault(def.TotalPages),
+		ReadPages:   m.ReadPages.orDefault(def.ReadPages),
+		PagesPerDay: m.PagesPerDay.orDefault(def.PagesPerDay),
+		Priority:    m.Priority.orDefault(def.Priority),
+		Mute:        m.Mute.orDefault(def.Mute),
+	}
+}
+
+// 各フィールドで扱える Notion のプロパティ型
+var allowedPropertyTypes = map[string][]string{
+	"task_name":     {PropertyTypeTitle, PropertyTypeRichText},
+	"due":           {PropertyTypeDate},
+	"status":        {PropertyTypeStatus, PropertyTypeSelect},
+	"project":       {PropertyTypeRelation, PropertyTypeSelect, PropertyTypeRichText},
+	"task_type":     {PropertyTypeSelect, PropertyTypeStatus},
+	"start_date":    {PropertyTypeDate},
+	"total_pages":   {PropertyTypeNumber},
+	"read_pages":    {PropertyTypeNumber},
+	"pages_per_day": {PropertyTypeNumber},
+	"priority":      {PropertyTypeSelect, PropertyTypeStatus},
+	"mute":          {PropertyTypeCheckbox},
+}
+
+// Validate はデフォルト補完後の各プロパティ型がそのフィールドで扱えるかを検証する。
+// 複数のフィールドが不正な場合は、毎回同じエラーになるよう定義順で最初のものを返す。
+func (m PropertyMapping) Validate() error {
+	m = m.withDefaults()
+	fields := []struct {
+		name string
+		prop Property
+	}{
+		{"task_name", m.TaskName},
+		{"due", m.Due},
+		{"status", m.Status},
+		{"project", m.Project},
+		{"task_type", m.TaskType},
+		{"start_date", m.StartDate},
+		{"total_pages", m.TotalPages},
+		{"read_pages", m.ReadPages},
+		{"pages_per_day", m.PagesPerDay},
+		{"priority", m.Priority},
+		{"mute", m.Mute},
+	}
+	for _, f := range fields {
+		if !containsString(allowedPropertyTypes[f.name], f.prop.Type) {
+			return fmt.Errorf("notion.properties.%s: unsupported type %q (allowed: %v)", f.name, f.prop.Type, allowedPropertyTypes[f.name])
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// propertyValue は Notion API が返すプロパティ値。type に応じていずれかのフィールドが埋まる。
+type propertyValue struct {
+	Type     string          `json:"type"`
+	Title    []richText      `json:"title,omitempty"`
+	RichText []richText      `json:"rich_text,omitempty"`
+	Date     *dateValue      `json:"date,omitempty"`
+	Status   *statusValue    `json:"status,omitempty"`
+	Select   *selectValue    `json:"select,omitempty"`
+	Relation []relationValue `json:"relation,omitempty"`
+	Number   *float64        `json:"number,omitempty"`
+	Checkbox bool            `json:"checkbox,omitempty"`
+}
+
+// text は title / rich_text / status / select を文字列として取り出す。
+func (v propertyValue) text() string {
+	switch {
+	case len(v.Title) > 0:
+		return v.Title[0].PlainText
+	case len(v.RichText) > 0:
+		return v.RichText[0].PlainText
+	case v.Status != nil:
+		return v.Status.Name
+	case v.Select != nil:
+		return v.Select.Name
+	}
+	return ""
+}
+
+func (v propertyValue) date(loc *time.Location) *time.Time {
+	if v.Date == nil || v.Date.Start == "" {
+		return nil
+	}
+	return parseDueDate(v.Date.Start, loc)
+}
+
+// dateHasTime は日付が時刻付き（RFC3339）で設定されているかどうかを返す。
+func (v propertyValue) dateHasTime() bool {
+	return v.Date != nil && strings.Contains(v.Date.Start, "T")
+}
+
+func (v propertyValue) number() (int, bool) {
+	if v.Number == nil {
+		return 0, false
+	}
+	return int(*v.Number), true
+}
+
+// checked はチェックボックスがオンかどうかを返す。
+func (v propertyValue) checked() bool {
+	return v.Checkbox
+}
+
+func (v propertyValue) firstRelationID() string {
+	if len(v.Relation) == 0 {
+		return ""
+	}
+	return v.Relation[0].ID
+}
+
+type relationValue struct {
+	ID string `json:"id"`
+}
+
+type richText struct {
+	PlainText string `json:"plain_text"`
+}
+
+type dateValue struct {
+	Start string `json:"start"`
+}
+
+type statusValue struct {
+	Name string `json:"name"`
+}
+
+type selectValue struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,164 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+// statusGroupsTTL はデータベースのスキーマから取得したステータスグループを使い回す期間。
+// Notion でステータスを追加・移動しても、serve を再起動せずにこの期間が過ぎれば反映される。
+const statusGroupsTTL = time.Hour
+
+// statusMapping はステータス名からグループへの対応表。対応表にないステータスは未完了として扱う。
+type statusMapping map[string]task.StatusGroup
+
+// 既定の Status 名の対応。Notion のスキーマや設定で上書きされる。
+func defaultStatusMapping() statusMapping {
+	return statusMapping{
+		string(task.StatusNotStarted): task.StatusGroupToDo,
+		string(task.StatusInProgress): task.StatusGroupInProgress,
+		string(task.StatusDone):       task.StatusGroupComplete,
+		string(task.StatusArchived):   task.StatusGroupComplete,
+	}
+}
+
+func (m statusMapping) group(name string) task.StatusGroup {
+	if g, ok := m[name]; ok {
+		return g
+	}
+	return task.StatusGroupToDo
+}
+
+// incompleteFilter は完了グループのステータスを除外するフィルタを返す。
+// 対応表にないステータスも通知対象に含めるため、未完了のステータスを列挙するのではなく完了を除外する。
+// 完了グループのステータスがない場合は nil。
+func (m statusMapping) incompleteFilter(prop Property) map[string]interface{} {
+	var complete []string
+	for name, g := range m {
+		if g == task.StatusGroupComplete {
+			complete = append(complete, name)
+		}
+	}
+	if len(complete) == 0 {
+		return nil
+	}
+	slices.Sort(complete)
+
+	conditions := make([]map[string]interface{}, 0, len(complete))
+	for _, name := range complete {
+		conditions = append(conditions, prop.filter(map[string]string{"does_not_equal": name}))
+	}
+	return map[string]interface{}{"and": conditions}
+}
+
+// statusMapping は既定の対応に、Status が status 型の場合は Notion のステータスグループを、
+// さらに WithStatusMapping で指定された対応を重ねたものを返す。
+func (c *Client) statusMapping(ctx context.Context) (statusMapping, error) {
+	m := defaultStatusMapping()
+	if c.props.Status.Type == PropertyTypeStatus {
+		groups, err := c.cachedStatusGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(m, groups)
+	}
+	maps.Copy(m, c.statusOverrides)
+	return m, nil
+}
+
+// cachedStatusGroups は statusGroupsTTL 以内に取得したステータスグループがあればそれを返し、
+// なければデータベースのスキーマから取得し直す。取得に失敗した場合はキャッシュしない。
+func (c *Client) cachedStatusGroups(ctx context.Context) (statusMapping, error) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	now := c.clock.Now()
+	if c.statusGroups != nil && now.Sub(c.statusFetchedAt) < statusGroupsTTL {
+		return c.statusGroups, nil
+	}
+	groups, err := c.fetchStatusGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status groups: %w", err)
+	}
+	c.statusGroups = groups
+	c.statusFetchedAt = now
+	return groups, nil
+}
+
+// Notion のステータスグループは常に「To-do」「In progress」「Complete」の順に 3 つある。
+// グループ名は変更できるため、名前ではなく順番で対応付ける。
+var statusGroupOrder = []task.StatusGroup{
+	task.StatusGroupToDo,
+	task.StatusGroupInProgress,
+	task.StatusGroupComplete,
+}
+
+// データベースのスキーマからステータスの選択肢と所属グループを取得する。
+func (c *Client) fetchStatusGroups(ctx context.Context) (statusMapping, error) {
+	url := fmt.Sprintf("%s/databases/%s", c.baseURL, c.databaseID)
+	resp, err := c.retry.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		req.Header.Set("Notion-Version", notionAPIVersion)
+
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch database: %d", resp.StatusCode)
+	}
+
+	var db struct {
+		Properties map[string]struct {
+			Type   string `json:"type"`
+			Status *struct {
+				Options []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"options"`
+				Groups []struct {
+					OptionIDs []string `json:"option_ids"`
+				} `json:"groups"`
+			} `json:"status"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&db); err != nil {
+		return nil, err
+	}
+
+	prop, ok := db.Properties[c.props.Status.Name]
+	if !ok || prop.Status == nil {
+		return nil, fmt.Errorf("status property %q not found in database", c.props.Status.Name)
+	}
+
+	names := make(map[string]string, len(prop.Status.Options))
+	for _, o := range prop.Status.Options {
+		names[o.ID] = o.Name
+	}
+	m := make(statusMapping)
+	for i, g := range prop.Status.Groups {
+		if i >= len(statusGroupOrder) {
+			break
+		}
+		for _, id := range g.OptionIDs {
+			if name, ok := names[id]; ok {
+				m[name] = statusGroupOrder[i]
+			}
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,69 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/task"
+)
+
+// UpdateStatus はステータス（status 型または select 型）を status に変更する。
+func (c *Client) UpdateStatus(ctx context.Context, taskID string, status task.Status) error {
+	return c.updateProperty(ctx, taskID, c.props.Status, map[string]interface{}{"name": string(status)})
+}
+
+// UpdateReadPages は読んだページ数の数値プロパティを readPages に変更する。
+func (c *Client) UpdateReadPages(ctx context.Context, taskID string, readPages int) error {
+	return c.updateProperty(ctx, taskID, c.props.ReadPages, readPages)
+}
+
+// PostponeDue は締切を due に変更する。時刻付きの場合は設定されたタイムゾーンの RFC3339 で書き込む。
+func (c *Client) PostponeDue(ctx context.Context, taskID string, due time.Time, hasTime bool) error {
+	start := due.In(c.location).Format("2006-01-02")
+	if hasTime {
+		start = due.In(c.location).Format(time.RFC3339)
+	}
+	return c.updateProperty(ctx, taskID, c.props.Due, map[string]interface{}{"start": start})
+}
+
+// updateProperty は PATCH /pages/{id} でプロパティを 1 つ更新する。
+// 値を上書きするだけなので、同じリクエストを再送しても結果は変わらない。
+func (c *Client) updateProperty(ctx context.Context, pageID string, prop Property, value interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			prop.Name: map[string]interface{}{prop.Type: value},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/pages/%s", c.baseURL, pageID)
+	resp, err := c.retry.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		req.Header.Set("Notion-Version", notionAPIVersion)
+		req.Header.Set("Content-Type", "application/json")
+
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("notion API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/webhook"
+)
+
+// Slack の上限
+const (
+	// section ブロックの text の文字数
+	maxSectionText = 3000
+	// 1 メッセージあたりのブロック数（attachments 内のブロックを含む）
+	maxBlocksPerMessage = 50
+)
+
+// WebhookClient は Slack の Incoming Webhook に Block Kit 形式で送信する。
+type WebhookClient struct {
+	httpClient *http.Client
+	webhookURL string
+	retry      httpretry.Policy
+}
+
+type Option func(*WebhookClient)
+
+// 429 などを受けたときの再試行方針を指定する。
+func WithRetryPolicy(p httpretry.Policy) Option {
+	return func(c *WebhookClient) {
+		c.retry = p
+	}
+}
+
+func NewWebhookClient(webhookURL string, opts ...Option) *WebhookClient {
+	c := &WebhookClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		webhookURL: webhookURL,
+		retry:      httpretry.DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Notify はタイトルを先頭のブロックに、各セクションを色付きの attachment として送信する。
+// text には通知のプレビューに表示されるプレーンテキストを入れる。
+// ブロック数が Slack の上限を超える場合は複数の投稿に分け、タイトルに "(1/3)" 形式の番号を付ける。
+// Mention は最初の投稿にだけ付ける。途中で失敗した場合は残りを送らずに返す。
+func (c *WebhookClient) Notify(ctx context.Context, message *notification.Message) error {
+	payloads := buildPayloads(message)
+	for i, p := range payloads {
+		body, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		if err := webhook.Post(ctx, c.httpClient, c.retry, c.webhookURL, body); err != nil {
+			if len(payloads) > 1 {
+				return fmt.Errorf("failed to send part %d/%d: %w", i+1, len(payloads), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+type payload struct {
+	Text        string       `json:"text"`
+	Blocks      []block      `json:"blocks,omitempty"`
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+type attachment struct {
+	Color  string  `json:"color,omitempty"`
+	Blocks []block `json:"blocks"`
+}
+
+type block struct {
+	Type string      `json:"type"`
+	Text *textObject `json:"text,omitempty"`
+}
+
+type textObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func mrkdwnSection(text string) block {
+	return block{Type: "section", Text: &textObject{Type: "mrkdwn", Text: text}}
+}
+
+func buildPayloads(message *notification.Message) []payload {
+	// 各投稿の先頭のタイトルのブロックの分を空けておく
+	maxBlocks := maxBlocksPerMessage - 1
+	var attachments []attachment
+	for _, sec := range message.Sections {
+		attachments = append(attachments, sectionAttachments(sec, maxBlocks)...)
+	}
+	batches := splitAttachments(attachments, maxBlocks)
+	if len(batches) == 0 {
+		batches = [][]attachment{nil}
+	}
+
+	payloads := make([]payload, 0, len(batches))
+	for i, batch := range batches {
+		title, text := mrkdwn(message.Title), plain(message.Title)
+		if len(batches) > 1 {
+			header := fmt.Sprintf("(%d/%d)", i+1, len(batches))
+			title, text = header+" "+title, header+" "+text
+		}
+		// メンションは最初の投稿にだけ付け、続きの投稿で何度も通知されないようにする
+		if i == 0 && message.Mention != "" {
+			title = mention(message.Mention) + " " + title
+		}
+		payloads = append(payloads, payload{
+			Text:        text,
+			Blocks:      []block{mrkdwnSection(title)},
+			Attachments: batch,
+		})
+	}
+	return payloads
+}
+
+// sectionAttachments はセクションを色付きの attachment にする。
+// ブロック数が maxBlocks を超える場合は同じ色の attachment に分ける（セクションのタイトルは最初の attachment にだけ付く）。
+func sectionAttachments(sec notification.Section, maxBlocks int) []attachment {
+	var blocks []block
+	if sec.Title != "" {
+		blocks = append(blocks, mrkdwnSection("*"+escape(sec.Title)+"*"))
+	}
+	lines := make([]string, 0, len(sec.Items))
+	for _, item := range sec.Items {
+		lines = append(lines, itemText(item))
+	}
+	for _, chunk := range chunkLines(lines, maxSectionText) {
+		blocks = append(blocks, mrkdwnSection(chunk))
+	}
+
+	var attachments []attachment
+	for len(blocks) > maxBlocks {
+		attachments = append(attachments, attachment{Color: color(sec.Color), Blocks: blocks[:maxBlocks]})
+		blocks = blocks[maxBlocks:]
+	}
+	return append(attachments, attachment{Color: color(sec.Color), Blocks: blocks})
+}
+
+// splitAttachments は 1 投稿あたりのブロック数が maxBlocks 以内になるよう attachment を投稿単位にまとめる。
+func splitAttachments(attachments []attachment, maxBlocks int) [][]attachment {
+	var batches [][]attachment
+	var current []attachment
+	currentBlocks := 0
+	for _, a := range attachments {
+		if len(current) > 0 && currentBlocks+len(a.Blocks) > maxBlocks {
+			batches = append(batches, current)
+			current = nil
+			currentBlocks = 0
+		}
+		current = append(current, a)
+		currentBlocks += len(a.Blocks)
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// itemText は "• [Work] <https://...|資料作成>: *本日締切* <https://...|✅ 完了>" の形式で 1 行を描画する。
+func itemText(item notification.Item) string {
+	var sb strings.Builder
+	sb.WriteString("• ")
+	if item.Project != "" {
+		sb.WriteString("[" + escape(item.Project) + "] ")
+	}
+	if item.URL != "" {
+		sb.WriteString("<" + item.URL + "|" + escape(item.Name) + ">")
+	} else {
+		sb.WriteString(escape(item.Name))
+	}
+	if item.Detail != "" {
+		sb.WriteString(": " + mrkdwn(item.Detail))
+	}
+	for i, a := range item.Actions {
+		sep := " · "
+		if i == 0 {
+			sep = " "
+		}
+		sb.WriteString(sep + "<" + a.URL + "|" + escape(a.Label) + ">")
+	}
+	return sb.String()
+}
+
+// chunkLines は 1 ブロックの上限（文字数）を超えないように行をまとめる。
+// 1 行で上限を超える場合は splitLine で複数に分ける。
+func chunkLines(lines []string, limit int) []string {
+	var chunks []string
+	var cur strings.Builder
+	curLen := 0
+	for _, line := range lines {
+		for _, part := range splitLine(line, limit) {
+			n := utf8.RuneCountInString(part)
+			if curLen > 0 && curLen+1+n > limit {
+				chunks = append(chunks, cur.String())
+				cur.Reset()
+				curLen = 0
+			}
+			if curLen > 0 {
+				cur.WriteString("\n")
+				curLen++
+			}
+			cur.WriteString(part)
+			curLen += n
+		}
+	}
+	if curLen > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// splitLine は limit 文字を超える行を、文字の途中やリンク（<url|text>）、エスケープ（&amp; など）の途中で
+// 切らないように分ける。リンク 1 つだけで limit を超える場合はやむを得ず文字単位で切る。
+func splitLine(line string, limit int) []string {
+	if utf8.RuneCountInString(line) <= limit {
+		return []string{line}
+	}
+	var parts []string
+	var cur strings.Builder
+	curLen := 0
+	for _, tok := range mrkdwnTokens(line) {
+		n := utf8.RuneCountInString(tok)
+		if curLen > 0 && curLen+n > limit {
+			parts = append(parts, cur.String())
+			cur.Reset()
+			curLen = 0
+		}
+		for n > limit {
+			runes := []rune(tok)
+			parts = append(parts, string(runes[:limit]))
+			tok = string(runes[limit:])
+			n -= limit
+		}
+		cur.WriteString(tok)
+		curLen += n
+	}
+	if curLen > 0 {
+		parts = append(parts, cur.String())
+	}
+	return parts
+}
+
+// mrkdwnTokens は s をリンク・エスケープ・それ以外の 1 文字ずつに分ける。
+// 本文の <, > と & は escape 済みのため、< で始まるのはリンクかメンションだけになる。
+func mrkdwnTokens(s string) []string {
+	var tokens []string
+	for len(s) > 0 {
+		size := 0
+		switch s[0] {
+		case '<':
+			if i := strings.IndexByte(s, '>'); i >= 0 {
+				size = i + 1
+			}
+		case '&':
+			if i := strings.IndexByte(s, ';'); i >= 0 && i <= len("&amp;") {
+				size = i + 1
+			}
+		}
+		if size == 0 {
+			_, size = utf8.DecodeRuneInString(s)
+		}
+		tokens = append(tokens, s[:size])
+		s = s[size:]
+	}
+	return tokens
+}
+
+// mrkdwn は Message 内の Markdown（**太字**）を Slack の mrkdwn（*太字*）に変換する。
+func mrkdwn(s string) string {
+	return strings.ReplaceAll(escape(s), "**", "*")
+}
+
+func plain(s string) string {
+	return strings.ReplaceAll(s, "**", "")
+}
+
+// Slack では &, <, > を制御文字として扱うためエスケープする。
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// mention は Discord 形式の "@here" などを Slack の特殊メンションに変換する。
+// それ以外（"<@U0123>" など）はそのまま使う。
+func mention(m string) string {
+	switch m {
+	case "@here", "@channel", "@everyone":
+		return "<!" + strings.TrimPrefix(m, "@") + ">"
+	}
+	return m
+}
+
+func color(c notification.Color) string {
+	if c == notification.ColorNone {
+		return ""
+	}
+	return fmt.Sprintf("#%06X", int(c))
+}
@@ -0,0 +1,225 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+func TestWebhookClient_Notify(t *testing.T) {
+	var received payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid JSON body %s: %v", body, err)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(server.URL, WithRetryPolicy(httpretry.NoRetry()))
+	err := client.Notify(context.Background(), &notification.Message{
+		Mention: "@here",
+		Title:   "📋 **締切が近いタスク一覧**",
+		Sections: []notification.Section{
+			{
+				Title: "本日締切",
+				Color: notification.ColorRed,
+				Items: []notification.Item{
+					{Name: "資料作成 <v2>", URL: "https://notion.so/1", Project: "Work", Detail: "🔴 **本日締切**"},
+					{Name: "買い物", Detail: "🔴 **本日締切**"},
+				},
+			},
+			{
+				Color: notification.ColorBlue,
+				Items: []notification.Item{{Name: "本"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Text != "📋 締切が近いタスク一覧" {
+		t.Errorf("text = %q", received.Text)
+	}
+	if len(received.Blocks) != 1 || received.Blocks[0].Text.Text != "<!here> 📋 *締切が近いタスク一覧*" {
+		t.Errorf("blocks = %+v", received.Blocks)
+	}
+	if len(received.Attachments) != 2 {
+		t.Fatalf("expected 2 attachments, got %d", len(received.Attachments))
+	}
+
+	today := received.Attachments[0]
+	if today.Color != "#E74C3C" {
+		t.Errorf("color = %q, want #E74C3C", today.Color)
+	}
+	if len(today.Blocks) != 2 || today.Blocks[0].Text.Text != "*本日締切*" {
+		t.Fatalf("blocks = %+v", today.Blocks)
+	}
+	wantItems := "• [Work] <https://notion.so/1|資料作成 &lt;v2&gt;>: 🔴 *本日締切*\n• 買い物: 🔴 *本日締切*"
+	if got := today.Blocks[1].Text.Text; got != wantItems {
+		t.Errorf("items = %q, want %q", got, wantItems)
+	}
+	// タイトルのないセクションは項目のブロックだけになる
+	if len(received.Attachments[1].Blocks) != 1 {
+		t.Errorf("expected untitled section to have 1 block, got %+v", received.Attachments[1].Blocks)
+	}
+}
+
+func TestWebhookClient_Notify_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service"))
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(server.URL, WithRetryPolicy(httpretry.NoRetry()))
+	err := client.Notify(context.Background(), &notification.Message{Title: "test"})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestWebhookClient_Notify_SplitsByBlockLimit(t *testing.T) {
+	var received []payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("invalid JSON body: %v", err)
+		}
+		received = append(received, p)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// 1 項目が 1500 文字を超えるため、1 ブロックに 1 項目ずつ入る
+	long := strings.Repeat("あ", 1600)
+	var sections []notification.Section
+	for i := 0; i < 3; i++ {
+		sec := notification.Section{Title: fmt.Sprintf("セクション%d", i), Color: notification.ColorRed}
+		for j := 0; j < 30; j++ {
+			sec.Items = append(sec.Items, notification.Item{Name: long})
+		}
+		sections = append(sections, sec)
+	}
+
+	client := NewWebhookClient(server.URL, WithRetryPolicy(httpretry.NoRetry()))
+	err := client.Notify(context.Background(), &notification.Message{
+		Mention:  "@here",
+		Title:    "📋 **締切が近いタスク一覧**",
+		Sections: sections,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 3 セクション × 31 ブロック = 93 ブロックは 49 ブロックずつでは 2 投稿に収まらない
+	if len(received) != 3 {
+		t.Fatalf("expected 3 posts, got %d", len(received))
+	}
+	var items int
+	for i, p := range received {
+		blocks := len(p.Blocks)
+		for _, a := range p.Attachments {
+			blocks += len(a.Blocks)
+			items += len(a.Blocks)
+		}
+		if blocks > maxBlocksPerMessage {
+			t.Errorf("post %d has %d blocks, want at most %d", i, blocks, maxBlocksPerMessage)
+		}
+		wantTitle := fmt.Sprintf("(%d/3) 📋 *締切が近いタスク一覧*", i+1)
+		if i == 0 {
+			wantTitle = "<!here> " + wantTitle
+		}
+		if got := p.Blocks[0].Text.Text; got != wantTitle {
+			t.Errorf("post %d title = %q, want %q", i, got, wantTitle)
+		}
+	}
+	if items != 3*31 {
+		t.Errorf("expected all %d section blocks to be sent, got %d", 3*31, items)
+	}
+}
+
+func TestSectionAttachments_SplitsLongSection(t *testing.T) {
+	sec := notification.Section{Title: "本日締切", Color: notification.ColorRed}
+	for i := 0; i < 5; i++ {
+		// "• " と合わせてちょうど 1 ブロックの上限になる
+		sec.Items = append(sec.Items, notification.Item{Name: strings.Repeat("a", maxSectionText-2)})
+	}
+	got := sectionAttachments(sec, 4)
+	if len(got) != 2 || len(got[0].Blocks) != 4 || len(got[1].Blocks) != 2 {
+		t.Fatalf("unexpected attachments: %+v", got)
+	}
+	if got[1].Color != got[0].Color || got[1].Blocks[0].Text.Text == "*本日締切*" {
+		t.Errorf("expected continuation attachment with the same color and no title, got %+v", got[1])
+	}
+}
+
+func TestChunkLines(t *testing.T) {
+	lines := []string{strings.Repeat("a", 6), strings.Repeat("b", 3), strings.Repeat("c", 12)}
+	got := chunkLines(lines, 10)
+	// 上限を超える行は切り捨てずに続きのブロックに分ける
+	want := []string{"aaaaaa\nbbb", strings.Repeat("c", 10), "cc"}
+	if len(got) != len(want) {
+		t.Fatalf("chunkLines() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunks[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestChunkLines_KeepsCharactersAndLinks(t *testing.T) {
+	link := "<https://example.com/actions?token=abc|✅ 完了>"
+	line := strings.Repeat("締", 8) + " " + link + " &amp; 資料"
+	limit := utf8.RuneCountInString(link) + 2
+	got := chunkLines([]string{line}, limit)
+
+	if strings.Join(got, "") != line {
+		t.Errorf("expected the line to be split without losing text, got %q", got)
+	}
+	for i, chunk := range got {
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunks[%d] = %q is not valid UTF-8", i, chunk)
+		}
+		if n := utf8.RuneCountInString(chunk); n > limit {
+			t.Errorf("chunks[%d] has %d characters, want at most %d", i, n, limit)
+		}
+		if strings.Count(chunk, "<") != strings.Count(chunk, ">") || strings.Count(chunk, "&") != strings.Count(chunk, ";") {
+			t.Errorf("chunks[%d] = %q splits a link or an escape", i, chunk)
+		}
+	}
+	if !slices.Contains(got, strings.Repeat("締", 8)+" ") {
+		t.Errorf("expected the text before the link in its own chunk, got %q", got)
+	}
+}
+
+func TestItemText_Actions(t *testing.T) {
+	item := notification.Item{
+		Name:   "資料作成",
+		Detail: "**本日締切**",
+		Actions: []notification.Action{
+			{Label: "✅ 完了", URL: "https://example.com/actions?token=a"},
+			{Label: "📅 1日延期", URL: "https://example.com/actions?token=b"},
+		},
+	}
+	want := "• 資料作成: *本日締切* <https://example.com/actions?token=a|✅ 完了> · <https://example.com/actions?token=b|📅 1日延期>"
+	if got := itemText(item); got != want {
+		t.Errorf("itemText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/webhook"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// WebhookClient は Microsoft Teams の Incoming Webhook（またはワークフローの Webhook）に
+// Adaptive Card として送信する。
+type WebhookClient struct {
+	httpClient *http.Client
+	webhookURL string
+	retry      httpretry.Policy
+}
+
+type Option func(*WebhookClient)
+
+// 429 などを受けたときの再試行方針を指定する。
+func WithRetryPolicy(p httpretry.Policy) Option {
+	return func(c *WebhookClient) {
+		c.retry = p
+	}
+}
+
+func NewWebhookClient(webhookURL string, opts ...Option) *WebhookClient {
+	c := &WebhookClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		webhookURL: webhookURL,
+		retry:      httpretry.DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Notify はタイトルを見出しに、各セクションを色（style）付きのコンテナとして 1 枚のカードで送信する。
+// Teams の Webhook は @here のような一斉メンションに対応していないため、Mention は見出しの先頭に文字列として付ける。
+func (c *WebhookClient) Notify(ctx context.Context, message *notification.Message) error {
+	body, err := json.Marshal(buildPayload(message))
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return webhook.Post(ctx, c.httpClient, c.retry, c.webhookURL, body)
+}
+
+type payload struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type attachment struct {
+	ContentType string `json:"contentType"`
+	Content     card   `json:"content"`
+}
+
+type card struct {
+	Schema  string    `json:"$schema"`
+	Type    string    `json:"type"`
+	Version string    `json:"version"`
+	Body    []element `json:"body"`
+}
+
+// element は TextBlock または Container。
+type element struct {
+	Type   string    `json:"type"`
+	Text   string    `json:"text,omitempty"`
+	Weight string    `json:"weight,omitempty"`
+	Size   string    `json:"size,omitempty"`
+	Wrap   bool      `json:"wrap,omitempty"`
+	Style  string    `json:"style,omitempty"`
+	Items  []element `json:"items,omitempty"`
+}
+
+func textBlock(text string) element {
+	return element{Type: "TextBlock", Text: text, Wrap: true}
+}
+
+func buildPayload(message *notification.Message) payload {
+	title := message.Title
+	if message.Mention != "" {
+		title = message.Mention + " " + title
+	}
+	heading := textBlock(title)
+	heading.Size = "Medium"
+	heading.Weight = "Bolder"
+
+	body := []element{heading}
+	for _, sec := range message.Sections {
+		container := element{Type: "Container", Style: style(sec.Color)}
+		if sec.Title != "" {
+			secTitle := textBlock(sec.Title)
+			secTitle.Weight = "Bolder"
+			container.Items = append(container.Items, secTitle)
+		}
+		// Adaptive Card の TextBlock は Markdown のリンクと太字に対応している
+		for _, item := range sec.Items {
+			container.Items = append(container.Items, textBlock(item.Markdown()))
+		}
+		body = append(body, container)
+	}
+
+	return payload{
+		Type: "message",
+		Attachments: []attachment{{
+			ContentType: adaptiveCardContentType,
+			Content: card{
+				Schema:  adaptiveCardSchema,
+				Type:    "AdaptiveCard",
+				Version: adaptiveCardVersion,
+				Body:    body,
+			},
+		}},
+	}
+}
+
+// style は色をコンテナのスタイルに対応付ける。Adaptive Card では任意の色を指定できない。
+func style(c notification.Color) string {
+	switch c {
+	case notification.ColorRed:
+		return "attention"
+	case notification.ColorOrange, notification.ColorYellow:
+		return "warning"
+	case notification.ColorBlue:
+		return "accent"
+	default:
+		return "default"
+	}
+}
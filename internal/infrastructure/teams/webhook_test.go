@@ -0,0 +1,83 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+func TestWebhookClient_Notify(t *testing.T) {
+	var received payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid JSON body %s: %v", body, err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(server.URL, WithRetryPolicy(httpretry.NoRetry()))
+	err := client.Notify(context.Background(), &notification.Message{
+		Title: "📋 **締切が近いタスク一覧**",
+		Sections: []notification.Section{
+			{
+				Title: "本日締切",
+				Color: notification.ColorRed,
+				Items: []notification.Item{{Name: "資料作成", URL: "https://notion.so/1", Project: "Work", Detail: "🔴 **本日締切**"}},
+			},
+			{
+				Title: "近日締切",
+				Color: notification.ColorYellow,
+				Items: []notification.Item{{Name: "買い物", Detail: "🟡 あと3日"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Type != "message" || len(received.Attachments) != 1 {
+		t.Fatalf("unexpected payload: %+v", received)
+	}
+	att := received.Attachments[0]
+	if att.ContentType != "application/vnd.microsoft.card.adaptive" || att.Content.Type != "AdaptiveCard" {
+		t.Errorf("unexpected attachment: %+v", att)
+	}
+
+	body := att.Content.Body
+	if len(body) != 3 {
+		t.Fatalf("expected heading and 2 containers, got %+v", body)
+	}
+	if body[0].Text != "📋 **締切が近いタスク一覧**" || body[0].Weight != "Bolder" {
+		t.Errorf("heading = %+v", body[0])
+	}
+
+	wantStyles := []string{"attention", "warning"}
+	for i, c := range body[1:] {
+		if c.Type != "Container" || c.Style != wantStyles[i] {
+			t.Errorf("containers[%d] = %+v, want style %s", i, c, wantStyles[i])
+		}
+	}
+	if items := body[1].Items; len(items) != 2 || items[1].Text != "- [Work] [資料作成](https://notion.so/1): 🔴 **本日締切**" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestWebhookClient_Notify_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(server.URL, WithRetryPolicy(httpretry.NoRetry()))
+	if err := client.Notify(context.Background(), &notification.Message{Title: "test"}); err == nil {
+		t.Error("expected error for 400 response")
+	}
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+// DefaultTemplate は本文テンプレートを指定しない場合に使う。{"text": "..."} の形で送る。
+const DefaultTemplate = `{"text": {{json .Text}}}`
+
+// Client は任意の Webhook に、Go テンプレートで組み立てた JSON を送信する。
+// テンプレートには *notification.Message が渡され、.Title, .Mention, .Sections, .Text を参照できる。
+// 文字列を JSON に埋め込むときは {{json .Title}} のように json 関数でエスケープする。
+type Client struct {
+	httpClient *http.Client
+	url        string
+	tmpl       *template.Template
+	retry      httpretry.Policy
+}
+
+type Option func(*Client)
+
+// 429 などを受けたときの再試行方針を指定する。
+func WithRetryPolicy(p httpretry.Policy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// NewClient は本文テンプレートを解析してクライアントを作る。tmpl が空の場合は DefaultTemplate を使う。
+func NewClient(url, tmpl string, opts ...Option) (*Client, error) {
+	if tmpl == "" {
+		tmpl = DefaultTemplate
+	}
+	t, err := ParseTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		url:        url,
+		tmpl:       t,
+		retry:      httpretry.DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// ParseTemplate は json 関数を使える本文テンプレートを解析する。
+func ParseTemplate(text string) (*template.Template, error) {
+	t, err := template.New("body").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook template: %w", err)
+	}
+	return t, nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Notify はテンプレートで本文を組み立てて送信する。結果が JSON として不正な場合は送信しない。
+func (c *Client) Notify(ctx context.Context, message *notification.Message) error {
+	var buf bytes.Buffer
+	if err := c.tmpl.Execute(&buf, message); err != nil {
+		return fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return fmt.Errorf("webhook template rendered invalid JSON: %s", buf.String())
+	}
+	return Post(ctx, c.httpClient, c.retry, c.url, buf.Bytes())
+}
+
+// Post は body を JSON として url に POST し、2xx 以外のステータスをエラーとして返す。
+// Webhook への投稿は冪等ではないため、未処理が明らかな場合（429 と接続エラー）のみ再送する。
+func Post(ctx context.Context, httpClient *http.Client, retry httpretry.Policy, url string, body []byte) error {
+	resp, err := retry.Do(ctx, false, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		return httpClient.Do(req)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/domain/notification"
+	"github.com/YutoOkawa/notion-notifier-for-personal-task/internal/infrastructure/httpretry"
+)
+
+func testMessage() *notification.Message {
+	return &notification.Message{
+		Title: "📋 **締切が近いタスク一覧**",
+		Sections: []notification.Section{{
+			Title: "本日締切",
+			Color: notification.ColorRed,
+			Items: []notification.Item{{Name: `"資料" 作成`, Project: "Work", Detail: "🔴 **本日締切**"}},
+		}},
+	}
+}
+
+func TestClient_Notify_DefaultTemplate(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected Content-Type application/json, got %q", ct)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid JSON body %s: %v", body, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "", WithRetryPolicy(httpretry.NoRetry()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg := testMessage()
+	if err := client.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received["text"] != msg.Text() {
+		t.Errorf("text = %q, want %q", received["text"], msg.Text())
+	}
+}
+
+func TestClient_Notify_CustomTemplate(t *testing.T) {
+	var received struct {
+		Title string   `json:"title"`
+		Tasks []string `json:"tasks"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid JSON body %s: %v", body, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tmpl := `{"title": {{json .Title}}, "tasks": [{{range $i, $s := .Sections}}{{range $j, $it := $s.Items}}{{if or $i $j}},{{end}}{{json $it.Name}}{{end}}{{end}}]}`
+	client, err := NewClient(server.URL, tmpl, WithRetryPolicy(httpretry.NoRetry()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Title != "📋 **締切が近いタスク一覧**" {
+		t.Errorf("title = %q", received.Title)
+	}
+	if len(received.Tasks) != 1 || received.Tasks[0] != `"資料" 作成` {
+		t.Errorf("tasks = %v", received.Tasks)
+	}
+}
+
+func TestClient_Notify_InvalidJSON(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// json 関数を使わずに埋め込むと引用符がエスケープされない
+	client, err := NewClient(server.URL, `{"text": "{{(index (index .Sections 0).Items 0).Name}}"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = client.Notify(context.Background(), testMessage())
+	if err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("expected invalid JSON error, got %v", err)
+	}
+	if called {
+		t.Error("expected nothing to be sent")
+	}
+}
+
+func TestNewClient_InvalidTemplate(t *testing.T) {
+	if _, err := NewClient("http://example.com", `{"text": {{json .Text}`); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+func TestClient_Notify_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "", WithRetryPolicy(httpretry.NoRetry()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Notify(context.Background(), testMessage()); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import "sync"
+
+// ringBuffer は直近 n 件の実行履歴を保持する。古いものから上書きされる。
+type ringBuffer struct {
+	mu      sync.Mutex
+	records []RunRecord
+	next    int
+	full    bool
+}
+
+func newRingBuffer(n int) *ringBuffer {
+	return &ringBuffer{records: make([]RunRecord, n)}
+}
+
+func (b *ringBuffer) add(r RunRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records[b.next] = r
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// list は新しい順に返す。
+func (b *ringBuffer) list() []RunRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := b.next
+	if b.full {
+		n = len(b.records)
+	}
+	out := make([]RunRecord, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, b.records[(b.next-i+len(b.records))%len(b.records)])
+	}
+	return out
+}
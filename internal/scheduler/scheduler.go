@@ -2,58 +2,318 @@ package scheduler
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
+const (
+	defaultTimeout     = 60 * time.Second
+	defaultHistorySize = 50
+)
+
+var (
+	// ErrJobNotFound は指定された名前のジョブが登録されていない場合に返される。
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning は OverlapSkip のジョブが実行中のため、実行しなかった場合に返される。
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// Job は 1 回分の処理を実行し、送信した通知の件数を返す。
 type Job interface {
-	Run(ctx context.Context) error
+	Run(ctx context.Context) (sent int, err error)
+}
+
+// JobFunc は関数を Job として扱うためのアダプタ。
+type JobFunc func(ctx context.Context) (int, error)
+
+func (f JobFunc) Run(ctx context.Context) (int, error) {
+	return f(ctx)
+}
+
+// OverlapPolicy は同じジョブの実行中に次の実行要求が来たときの扱い。
+// スケジュール実行と RunNow / RunJob のどちらにも適用される。
+type OverlapPolicy string
+
+const (
+	OverlapSkip  OverlapPolicy = "skip"  // 実行せずに ErrJobRunning を返す（デフォルト）
+	OverlapQueue OverlapPolicy = "queue" // 実行中の処理が終わるのを待ってから実行する
+)
+
+// JobSpec は名前付きジョブの登録内容。
+// Enabled が false のジョブはスケジュール実行されないが、RunJob で手動実行はできる。
+type JobSpec struct {
+	Name     string
+	Schedule string
+	Timeout  time.Duration
+	Enabled  bool
+	Overlap  OverlapPolicy
+	Job      Job
+}
+
+// Trigger はジョブが実行されたきっかけ。
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerManual   Trigger = "manual"
+)
+
+// RunRecord はジョブ 1 回分の実行結果。
+type RunRecord struct {
+	Job      string
+	Trigger  Trigger
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Sent     int
+	Skipped  bool
+	Err      error
+}
+
+// JobStatus はジョブの現在の状態。
+type JobStatus struct {
+	Name     string
+	Schedule string
+	Enabled  bool
+	Overlap  OverlapPolicy
+	Running  bool
+	Next     time.Time // スケジュールされていない場合はゼロ値
+}
+
+type Option func(*Scheduler)
+
+// WithHistorySize は保持する実行履歴の件数を設定する。
+func WithHistorySize(n int) Option {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.history = newRingBuffer(n)
+		}
+	}
+}
+
+// WithLogger はスケジューラのログ（起動・停止、ジョブの実行結果）を出力するロガーを設定する。省略時は slog.Default()。
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Scheduler) {
+		if l != nil {
+			s.logger = l
+		}
+	}
+}
+
+type job struct {
+	spec    JobSpec
+	mu      sync.Mutex // 実行中は保持する
+	entryID cron.EntryID
+
+	stateMu sync.Mutex
+	running bool
 }
 
 type Scheduler struct {
-	cron     *cron.Cron
-	job      Job
-	schedule string
+	cron    *cron.Cron
+	jobs    []*job
+	history *ringBuffer
+	logger  *slog.Logger
 }
 
-func New(schedule string, job Job) *Scheduler {
-	return &Scheduler{
-		cron:     cron.New(cron.WithLocation(time.FixedZone("JST", 9*60*60))),
-		job:      job,
-		schedule: schedule,
+// スケジュールは loc のタイムゾーンで解釈される。
+func New(loc *time.Location, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		cron:    cron.New(cron.WithLocation(loc)),
+		history: newRingBuffer(defaultHistorySize),
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
+
+// Register はジョブを登録する。Start より前に呼ぶこと。
+func (s *Scheduler) Register(spec JobSpec) error {
+	if spec.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if spec.Job == nil {
+		return fmt.Errorf("job %q: job is required", spec.Name)
+	}
+	if _, err := s.find(spec.Name); err == nil {
+		return fmt.Errorf("job %q is already registered", spec.Name)
+	}
+	if _, err := cron.ParseStandard(spec.Schedule); err != nil {
+		return fmt.Errorf("job %q: invalid schedule %q: %w", spec.Name, spec.Schedule, err)
+	}
+	switch spec.Overlap {
+	case "":
+		spec.Overlap = OverlapSkip
+	case OverlapSkip, OverlapQueue:
+	default:
+		return fmt.Errorf("job %q: unsupported overlap policy %q", spec.Name, spec.Overlap)
+	}
+	if spec.Timeout <= 0 {
+		spec.Timeout = defaultTimeout
+	}
+
+	s.jobs = append(s.jobs, &job{spec: spec})
+	return nil
 }
 
 func (s *Scheduler) Start() error {
-	_, err := s.cron.AddFunc(s.schedule, func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		defer cancel()
+	for _, j := range s.jobs {
+		if !j.spec.Enabled {
+			s.logger.Info("job is disabled", slog.String("job", j.spec.Name))
+			continue
+		}
 
-		log.Println("Running scheduled job...")
-		if err := s.job.Run(ctx); err != nil {
-			log.Printf("job error: %v", err)
-		} else {
-			log.Println("Job completed successfully")
+		j := j
+		id, err := s.cron.AddFunc(j.spec.Schedule, func() {
+			_ = s.execute(j, TriggerSchedule)
+		})
+		if err != nil {
+			return fmt.Errorf("job %q: %w", j.spec.Name, err)
 		}
-	})
-	if err != nil {
-		return err
+		j.entryID = id
 	}
 
 	s.cron.Start()
-	log.Printf("Scheduler started. Schedule: %s, Next run: %v", s.schedule, s.cron.Entries()[0].Next)
+	for _, j := range s.jobs {
+		if j.entryID != 0 {
+			s.logger.Info("job scheduled",
+				slog.String("job", j.spec.Name),
+				slog.String("schedule", j.spec.Schedule),
+				slog.Time("next", s.cron.Entry(j.entryID).Next))
+		}
+	}
+	s.logger.Info("scheduler started")
 	return nil
 }
 
 func (s *Scheduler) Stop() {
-	s.cron.Stop()
-	log.Println("Scheduler stopped")
+	<-s.cron.Stop().Done()
+	s.logger.Info("scheduler stopped")
 }
 
+// RunNow は有効なジョブをすべて順番に実行する。失敗したジョブがあっても残りは実行する。
 func (s *Scheduler) RunNow() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	var errs []error
+	for _, j := range s.jobs {
+		if !j.spec.Enabled {
+			continue
+		}
+		if err := s.execute(j, TriggerManual); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", j.spec.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunJob は name のジョブを即時実行する。
+func (s *Scheduler) RunJob(name string) error {
+	j, err := s.find(name)
+	if err != nil {
+		return err
+	}
+	return s.execute(j, TriggerManual)
+}
+
+// Jobs は登録されているジョブの状態を登録順で返す。
+func (s *Scheduler) Jobs() []JobStatus {
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		st := JobStatus{
+			Name:     j.spec.Name,
+			Schedule: j.spec.Schedule,
+			Enabled:  j.spec.Enabled,
+			Overlap:  j.spec.Overlap,
+			Running:  j.isRunning(),
+		}
+		if j.entryID != 0 {
+			st.Next = s.cron.Entry(j.entryID).Next
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// Runs は直近の実行履歴を新しい順に返す。
+func (s *Scheduler) Runs() []RunRecord {
+	return s.history.list()
+}
+
+func (s *Scheduler) find(name string) (*job, error) {
+	for _, j := range s.jobs {
+		if j.spec.Name == name {
+			return j, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+}
+
+// execute は重複実行のポリシーに従ってジョブを 1 回実行し、結果を履歴とログに残す。
+func (s *Scheduler) execute(j *job, trigger Trigger) error {
+	if j.spec.Overlap == OverlapQueue {
+		j.mu.Lock()
+	} else if !j.mu.TryLock() {
+		now := time.Now()
+		s.record(RunRecord{Job: j.spec.Name, Trigger: trigger, Start: now, End: now, Skipped: true, Err: ErrJobRunning})
+		return ErrJobRunning
+	}
+	defer j.mu.Unlock()
+
+	j.setRunning(true)
+	defer j.setRunning(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.spec.Timeout)
 	defer cancel()
-	return s.job.Run(ctx)
+
+	start := time.Now()
+	sent, err := j.spec.Job.Run(ctx)
+	end := time.Now()
+	s.record(RunRecord{
+		Job:      j.spec.Name,
+		Trigger:  trigger,
+		Start:    start,
+		End:      end,
+		Duration: end.Sub(start),
+		Sent:     sent,
+		Err:      err,
+	})
+	return err
+}
+
+func (s *Scheduler) record(r RunRecord) {
+	s.history.add(r)
+
+	attrs := []any{
+		slog.String("job", r.Job),
+		slog.String("trigger", string(r.Trigger)),
+		slog.Time("start", r.Start),
+		slog.Duration("duration", r.Duration),
+		slog.Int("sent", r.Sent),
+	}
+	switch {
+	case r.Skipped:
+		s.logger.Warn("job skipped: previous run still in progress", attrs...)
+	case r.Err != nil:
+		s.logger.Error("job failed", append(attrs, slog.String("error", r.Err.Error()))...)
+	default:
+		s.logger.Info("job completed", attrs...)
+	}
+}
+
+func (j *job) setRunning(running bool) {
+	j.stateMu.Lock()
+	defer j.stateMu.Unlock()
+	j.running = running
+}
+
+func (j *job) isRunning() bool {
+	j.stateMu.Lock()
+	defer j.stateMu.Unlock()
+	return j.running
 }
@@ -0,0 +1,250 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingJob struct {
+	runs int
+	sent int
+	err  error
+}
+
+func (j *countingJob) Run(ctx context.Context) (int, error) {
+	j.runs++
+	return j.sent, j.err
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := New(time.UTC)
+	if err := s.Register(JobSpec{Name: "deadlines", Schedule: "0 9 * * *", Job: &countingJob{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec JobSpec
+	}{
+		{name: "missing name", spec: JobSpec{Schedule: "0 9 * * *", Job: &countingJob{}}},
+		{name: "duplicate name", spec: JobSpec{Name: "deadlines", Schedule: "0 21 * * *", Job: &countingJob{}}},
+		{name: "invalid schedule", spec: JobSpec{Name: "reading", Schedule: "every day", Job: &countingJob{}}},
+		{name: "missing job", spec: JobSpec{Name: "overdue", Schedule: "0 9 * * *"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Register(tt.spec); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestScheduler_RunNowAndRunJob(t *testing.T) {
+	deadlines := &countingJob{err: errors.New("boom")}
+	reading := &countingJob{}
+	digest := &countingJob{}
+
+	s := New(time.UTC)
+	for _, spec := range []JobSpec{
+		{Name: "deadlines", Schedule: "0 9 * * *", Enabled: true, Job: deadlines},
+		{Name: "reading", Schedule: "0 21 * * *", Enabled: true, Job: reading},
+		{Name: "digest", Schedule: "0 9 * * 1", Enabled: false, Job: digest},
+	} {
+		if err := s.Register(spec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// 失敗したジョブがあっても残りの有効なジョブは実行される
+	if err := s.RunNow(); err == nil {
+		t.Error("expected error from failing job")
+	}
+	if deadlines.runs != 1 || reading.runs != 1 || digest.runs != 0 {
+		t.Errorf("unexpected runs: deadlines=%d reading=%d digest=%d", deadlines.runs, reading.runs, digest.runs)
+	}
+
+	// 無効なジョブも名前を指定すれば実行できる
+	if err := s.RunJob("digest"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if digest.runs != 1 {
+		t.Errorf("expected digest to run once, got %d", digest.runs)
+	}
+
+	if err := s.RunJob("unknown"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestScheduler_RunJobTimeout(t *testing.T) {
+	s := New(time.UTC)
+	job := JobFunc(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if err := s.Register(JobSpec{Name: "slow", Schedule: "0 9 * * *", Timeout: 10 * time.Millisecond, Job: job}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.RunJob("slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// blockingJob は release が閉じられるまで終わらない。
+type blockingJob struct {
+	started chan struct{}
+	release chan struct{}
+	mu      sync.Mutex
+	runs    int
+}
+
+func newBlockingJob() *blockingJob {
+	return &blockingJob{started: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (j *blockingJob) Run(ctx context.Context) (int, error) {
+	j.mu.Lock()
+	j.runs++
+	j.mu.Unlock()
+	j.started <- struct{}{}
+	<-j.release
+	return 1, nil
+}
+
+func TestScheduler_OverlapSkip(t *testing.T) {
+	job := newBlockingJob()
+	s := New(time.UTC)
+	if err := s.Register(JobSpec{Name: "deadlines", Schedule: "0 9 * * *", Enabled: true, Job: job}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- s.RunJob("deadlines") }()
+	<-job.started
+
+	if !s.Jobs()[0].Running {
+		t.Error("expected job to be reported as running")
+	}
+	if err := s.RunJob("deadlines"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("expected ErrJobRunning, got %v", err)
+	}
+
+	close(job.release)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if job.runs != 1 {
+		t.Errorf("expected 1 run, got %d", job.runs)
+	}
+
+	runs := s.Runs()
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(runs))
+	}
+	// 新しい順: 完了した実行、スキップされた実行
+	if runs[0].Skipped || runs[0].Sent != 1 || runs[0].Err != nil {
+		t.Errorf("unexpected completed record: %+v", runs[0])
+	}
+	if !runs[1].Skipped || !errors.Is(runs[1].Err, ErrJobRunning) {
+		t.Errorf("unexpected skipped record: %+v", runs[1])
+	}
+}
+
+func TestScheduler_OverlapQueue(t *testing.T) {
+	job := newBlockingJob()
+	s := New(time.UTC)
+	if err := s.Register(JobSpec{Name: "reading", Schedule: "0 21 * * *", Enabled: true, Overlap: OverlapQueue, Job: job}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 2)
+	go func() { done <- s.RunJob("reading") }()
+	<-job.started
+	go func() { done <- s.RunNow() }()
+
+	// 2 回目は 1 回目が終わるまで始まらない
+	select {
+	case <-job.started:
+		t.Fatal("queued run started while the first run was in progress")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(job.release)
+	for range 2 {
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if job.runs != 2 {
+		t.Errorf("expected 2 runs, got %d", job.runs)
+	}
+}
+
+func TestScheduler_RunsRingBuffer(t *testing.T) {
+	job := &countingJob{}
+	s := New(time.UTC, WithHistorySize(3))
+	if err := s.Register(JobSpec{Name: "deadlines", Schedule: "0 9 * * *", Job: job}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		job.sent = i
+		if err := s.RunJob("deadlines"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	runs := s.Runs()
+	if len(runs) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(runs))
+	}
+	for i, want := range []int{5, 4, 3} {
+		if runs[i].Sent != want {
+			t.Errorf("runs[%d].Sent = %d, want %d", i, runs[i].Sent, want)
+		}
+		if runs[i].Trigger != TriggerManual {
+			t.Errorf("runs[%d].Trigger = %s, want manual", i, runs[i].Trigger)
+		}
+	}
+}
+
+func TestScheduler_LogsRunsAsStructuredRecords(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(time.UTC, WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	if err := s.Register(JobSpec{Name: "deadlines", Schedule: "0 9 * * *", Job: &countingJob{sent: 2, err: errors.New("boom")}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.RunJob("deadlines"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse log output %q: %v", buf.String(), err)
+	}
+	for key, want := range map[string]any{
+		"level":   "ERROR",
+		"msg":     "job failed",
+		"job":     "deadlines",
+		"trigger": "manual",
+		"sent":    float64(2),
+		"error":   "boom",
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %v", key, entry[key], want)
+		}
+	}
+	for _, key := range []string{"start", "duration"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("expected %s in log output", key)
+		}
+	}
+}